@@ -0,0 +1,144 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"go.elastic.co/apm"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+const (
+	// APMSpanType is the type of the Elastic APM spans.
+	APMSpanType = "external.http"
+
+	// ElasticTraceparentHeader is the legacy Elastic APM trace context header.
+	ElasticTraceparentHeader = "Elastic-Apm-Traceparent"
+
+	// TraceparentHeader is the W3C Trace Context header.
+	TraceparentHeader = "Traceparent"
+
+	// TracestateHeader is the W3C Trace Context vendor-specific header.
+	TracestateHeader = "Tracestate"
+)
+
+//////
+// Methods.
+//////
+
+// startAPMSpan starts the Elastic APM span of a request, returning the context
+// carrying it, and the function ending it. No-op if there's no
+// `apm.Transaction` in `ctx`.
+func (c *Client) startAPMSpan(ctx context.Context, req *http.Request) (context.Context, endSpanFunc) {
+	if apm.TransactionFromContext(ctx) == nil {
+		return ctx, func(*http.Response, error) {}
+	}
+
+	span, ctx := apm.StartSpan(ctx, apmSpanName(req), APMSpanType)
+
+	return ctx, c.endAPMSpan(ctx, span, req, true)
+}
+
+// startAPMAttemptSpan starts the Elastic APM exit span of an attempt, child of
+// the request span in the `req` context, propagating the trace context via the
+// `req` headers. No-op if there's no `apm.Transaction` in the context.
+//
+// NOTE: If OpenTelemetry tracing is also enabled, the W3C headers set here
+// take precedence.
+func (c *Client) startAPMAttemptSpan(req *http.Request, attempt int) (*http.Request, endSpanFunc) {
+	tx := apm.TransactionFromContext(req.Context())
+	if tx == nil {
+		return req, func(*http.Response, error) {}
+	}
+
+	span, ctx := apm.StartSpanOptions(req.Context(), apmSpanName(req), APMSpanType, apm.SpanOptions{ExitSpan: true})
+
+	span.Context.SetLabel("attempt", attempt)
+
+	// Dropped spans, e.g.: not sampled, propagate the transaction.
+	traceContext := tx.TraceContext()
+
+	if !span.Dropped() {
+		traceContext = span.TraceContext()
+	}
+
+	req = req.WithContext(ctx)
+
+	setAPMTraceContextHeaders(req.Header, traceContext)
+
+	return req, c.endAPMSpan(ctx, span, req, false)
+}
+
+// endAPMSpan returns the function ending `span`, recording the status, and
+// the destination. Status codes >= 400, and errors mark the span as failed.
+// Errors are only captured if `captureError`, avoiding duplicates.
+//
+// NOTE: The URL is redacted, see `Redactor`.
+func (c *Client) endAPMSpan(ctx context.Context, span *apm.Span, req *http.Request, captureError bool) endSpanFunc {
+	rawURL := req.URL.String()
+
+	// Shallow copy, only the URL is read.
+	redactedReq := *req
+
+	if u, err := url.Parse(c.Redactor.URL(rawURL)); err == nil {
+		redactedReq.URL = u
+	}
+
+	return func(resp *http.Response, err error) {
+		defer span.End()
+
+		if span.Dropped() {
+			return
+		}
+
+		span.Context.SetHTTPRequest(&redactedReq)
+
+		statusCode := statusCodeOf(resp, err)
+
+		if statusCode > 0 {
+			span.Context.SetHTTPStatusCode(statusCode)
+		}
+
+		if err != nil {
+			span.Outcome = "failure"
+
+			if captureError {
+				e := apm.CaptureError(ctx, fmt.Errorf("%s", c.Redactor.Message(err.Error(), rawURL)))
+				e.Send()
+			}
+		}
+	}
+}
+
+//////
+// Helpers.
+//////
+
+// apmSpanName returns the span name, e.g.: `GET example.com`.
+func apmSpanName(req *http.Request) string {
+	return fmt.Sprintf("%s %s", req.Method, req.URL.Host)
+}
+
+// setAPMTraceContextHeaders sets the W3C, and the legacy Elastic trace context
+// headers.
+func setAPMTraceContextHeaders(header http.Header, traceContext apm.TraceContext) {
+	flags := "00"
+
+	if traceContext.Options.Recorded() {
+		flags = "01"
+	}
+
+	traceparent := fmt.Sprintf("00-%s-%s-%s", traceContext.Trace, traceContext.Span, flags)
+
+	header.Set(TraceparentHeader, traceparent)
+	header.Set(ElasticTraceparentHeader, traceparent)
+
+	if state := traceContext.State.String(); state != "" {
+		header.Set(TracestateHeader, state)
+	}
+}
@@ -0,0 +1,133 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.elastic.co/apm/apmtest"
+	"go.elastic.co/apm/model"
+)
+
+func TestClient_Get_apm(t *testing.T) {
+	var (
+		hits    int32
+		mu      sync.Mutex
+		headers []http.Header
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers = append(headers, r.Header.Clone())
+		mu.Unlock()
+
+		// Fails the first attempt.
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := Initialize(WithClientName("apm"), WithClientRetrier(100*time.Millisecond, 1))
+	assert.NoError(t, err)
+
+	tracer := apmtest.NewRecordingTracer()
+	defer tracer.Close()
+
+	tx, spans, errs := tracer.WithTransaction(func(ctx context.Context) {
+		resp, err := c.Get(ctx, server.URL+"?token=secret")
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+	})
+
+	assert.Empty(t, errs)
+
+	// 2 attempts, and the request.
+	assert.Len(t, spans, 3)
+
+	firstAttempt, secondAttempt, request := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, tx.ID, request.ParentID)
+	assert.Equal(t, "external", request.Type)
+	assert.Equal(t, "http", request.Subtype)
+	assert.Equal(t, "success", request.Outcome)
+	assert.Equal(t, "GET "+server.Listener.Addr().String(), request.Name)
+
+	for i, attempt := range []model.Span{firstAttempt, secondAttempt} {
+		assert.Equal(t, request.ID, attempt.ParentID)
+
+		// Each attempt propagates its own span.
+		traceparent := fmt.Sprintf("00-%x-%x-01", tx.TraceID, attempt.ID)
+
+		assert.Equal(t, traceparent, headers[i].Get(TraceparentHeader))
+		assert.Equal(t, traceparent, headers[i].Get(ElasticTraceparentHeader))
+	}
+
+	assert.Equal(t, "failure", firstAttempt.Outcome)
+	assert.Equal(t, http.StatusInternalServerError, firstAttempt.Context.HTTP.StatusCode)
+	assert.Equal(t, "success", secondAttempt.Outcome)
+	assert.Equal(t, http.StatusOK, secondAttempt.Context.HTTP.StatusCode)
+	assert.Equal(t, "token=[REDACTED]", secondAttempt.Context.HTTP.URL.RawQuery)
+	assert.Equal(t, server.Listener.Addr().String(), secondAttempt.Context.Destination.Service.Resource)
+}
+
+func TestClient_Get_apmFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c, err := Initialize(WithClientName("apmfailure"))
+	assert.NoError(t, err)
+
+	tracer := apmtest.NewRecordingTracer()
+	defer tracer.Close()
+
+	_, spans, errs := tracer.WithTransaction(func(ctx context.Context) {
+		//nolint:bodyclose
+		_, err := c.Get(ctx, server.URL)
+		assert.True(t, IsNotFound(err))
+	})
+
+	assert.Len(t, spans, 2)
+
+	for _, span := range spans {
+		assert.Equal(t, "failure", span.Outcome)
+		assert.Equal(t, http.StatusNotFound, span.Context.HTTP.StatusCode)
+	}
+
+	// Captured once, by the request span.
+	assert.Len(t, errs, 1)
+	assert.Equal(t, spans[1].ID, errs[0].ParentID)
+}
+
+func TestClient_Get_apmNoTransaction(t *testing.T) {
+	var header http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := Initialize(WithClientName("apmnotransaction"))
+	assert.NoError(t, err)
+
+	resp, err := c.Get(context.Background(), server.URL)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Empty(t, header.Get(TraceparentHeader))
+	assert.Empty(t, header.Get(ElasticTraceparentHeader))
+}
@@ -0,0 +1,128 @@
+package httpclient
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// Backoff computes how long to wait before a retry.
+type Backoff interface {
+	// Delay returns how long to wait before the `retry`th retry, starting at 1.
+	// `previous` is the previous delay, zero for the first retry.
+	Delay(retry int, previous time.Duration) time.Duration
+}
+
+// BackoffFunc allows to use a function as a `Backoff`.
+type BackoffFunc func(retry int, previous time.Duration) time.Duration
+
+// Delay implements the Backoff interface.
+func (f BackoffFunc) Delay(retry int, previous time.Duration) time.Duration {
+	return f(retry, previous)
+}
+
+//////
+// Built-in backoffs.
+//////
+
+// ConstantBackoff always waits `d`, e.g.: 1s -> 1s -> 1s.
+func ConstantBackoff(d time.Duration) Backoff {
+	return BackoffFunc(func(retry int, previous time.Duration) time.Duration {
+		return d
+	})
+}
+
+// LinearBackoff waits `base` times the retry number, e.g.: 1s -> 2s -> 3s.
+func LinearBackoff(base time.Duration) Backoff {
+	return BackoffFunc(func(retry int, previous time.Duration) time.Duration {
+		return multiply(base, float64(retry))
+	})
+}
+
+// ExponentialBackoff doubles the wait at each retry, e.g.: 1s -> 2s -> 4s.
+// It's the default.
+func ExponentialBackoff(base time.Duration) Backoff {
+	return BackoffFunc(func(retry int, previous time.Duration) time.Duration {
+		return exponential(base, retry)
+	})
+}
+
+// ExponentialFullJitterBackoff waits a random amount between zero, and the
+// exponential backoff.
+//
+// SEE: https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+func ExponentialFullJitterBackoff(base time.Duration) Backoff {
+	return BackoffFunc(func(retry int, previous time.Duration) time.Duration {
+		return randomBetween(0, exponential(base, retry))
+	})
+}
+
+// ExponentialEqualJitterBackoff waits half of the exponential backoff, plus a
+// random amount up to the other half.
+//
+// SEE: https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+func ExponentialEqualJitterBackoff(base time.Duration) Backoff {
+	return BackoffFunc(func(retry int, previous time.Duration) time.Duration {
+		half := exponential(base, retry) / 2
+
+		return half + randomBetween(0, half)
+	})
+}
+
+// DecorrelatedJitterBackoff waits a random amount between `base`, and three
+// times the previous delay.
+//
+// SEE: https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+func DecorrelatedJitterBackoff(base time.Duration) Backoff {
+	return BackoffFunc(func(retry int, previous time.Duration) time.Duration {
+		if previous < base {
+			previous = base
+		}
+
+		return randomBetween(base, multiply(previous, 3))
+	})
+}
+
+//////
+// Helpers.
+//////
+
+// multiply `d` by `factor`, without overflowing.
+func multiply(d time.Duration, factor float64) time.Duration {
+	result := float64(d) * factor
+
+	if result >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+
+	return time.Duration(result)
+}
+
+// exponential returns `base` * 2^(`retry` - 1), without overflowing.
+func exponential(base time.Duration, retry int) time.Duration {
+	if retry < 1 {
+		retry = 1
+	}
+
+	return multiply(base, math.Pow(2, float64(retry-1)))
+}
+
+// randomBetween returns a random duration in the [min, max] interval.
+func randomBetween(min, max time.Duration) time.Duration {
+	if max <= min {
+		return min
+	}
+
+	span := int64(max - min)
+
+	if span < math.MaxInt64 {
+		span++
+	}
+
+	//nolint:gosec
+	return min + time.Duration(rand.Int63n(span))
+}
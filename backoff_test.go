@@ -0,0 +1,161 @@
+package httpclient
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffs(t *testing.T) {
+	base := 100 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		backoff  Backoff
+		retry    int
+		previous time.Duration
+		min      time.Duration
+		max      time.Duration
+	}{
+		{
+			name:    "ConstantBackoff",
+			backoff: ConstantBackoff(base),
+			retry:   3,
+			min:     base,
+			max:     base,
+		},
+		{
+			name:    "LinearBackoff",
+			backoff: LinearBackoff(base),
+			retry:   3,
+			min:     3 * base,
+			max:     3 * base,
+		},
+		{
+			name:    "ExponentialBackoff",
+			backoff: ExponentialBackoff(base),
+			retry:   3,
+			min:     4 * base,
+			max:     4 * base,
+		},
+		{
+			name:    "ExponentialBackoff - should not overflow",
+			backoff: ExponentialBackoff(base),
+			retry:   1000,
+			min:     time.Duration(math.MaxInt64),
+			max:     time.Duration(math.MaxInt64),
+		},
+		{
+			name:    "ExponentialFullJitterBackoff",
+			backoff: ExponentialFullJitterBackoff(base),
+			retry:   3,
+			min:     0,
+			max:     4 * base,
+		},
+		{
+			name:    "ExponentialEqualJitterBackoff",
+			backoff: ExponentialEqualJitterBackoff(base),
+			retry:   3,
+			min:     2 * base,
+			max:     4 * base,
+		},
+		{
+			name:     "DecorrelatedJitterBackoff",
+			backoff:  DecorrelatedJitterBackoff(base),
+			retry:    3,
+			previous: 2 * base,
+			min:      base,
+			max:      6 * base,
+		},
+		{
+			name: "BackoffFunc",
+			backoff: BackoffFunc(func(retry int, previous time.Duration) time.Duration {
+				return previous + time.Duration(retry)*time.Millisecond
+			}),
+			retry:    3,
+			previous: base,
+			min:      base + 3*time.Millisecond,
+			max:      base + 3*time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Jitter is random, run it a few times.
+			for i := 0; i < 100; i++ {
+				got := tt.backoff.Delay(tt.retry, tt.previous)
+
+				assert.GreaterOrEqual(t, got, tt.min)
+				assert.LessOrEqual(t, got, tt.max)
+			}
+		})
+	}
+}
+
+func TestClient_Get_backoff(t *testing.T) {
+	tests := []struct {
+		name             string
+		clientName       string
+		maxBudget        time.Duration
+		opts             []Func
+		expectedAttempts int32
+	}{
+		{
+			name:             "should retry until exhausted",
+			clientName:       "backoffexhausted",
+			expectedAttempts: 4,
+		},
+		{
+			name:             "should stop when the retry budget is exceeded",
+			clientName:       "backoffbudget",
+			maxBudget:        500 * time.Millisecond,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "should stop when the per-request retry budget is exceeded",
+			clientName:       "backoffrequestbudget",
+			opts:             []Func{WithRetrierMaxBudget(700 * time.Millisecond)},
+			expectedAttempts: 3,
+		},
+		{
+			name:       "should use the per-request backoff",
+			clientName: "backoffrequest",
+			maxBudget:  500 * time.Millisecond,
+			opts: []Func{
+				WithBackoff(ConstantBackoff(100 * time.Millisecond)),
+			},
+			expectedAttempts: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			c, err := New(tt.clientName, nil, 0, 100*time.Millisecond, 3)
+			assert.NoError(t, err)
+
+			c.Backoff = ConstantBackoff(300 * time.Millisecond)
+			c.RetrierMaxBudget = tt.maxBudget
+
+			_, err = c.Get(ctx, server.URL, tt.opts...)
+			assert.Error(t, err)
+
+			assert.Equal(t, tt.expectedAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/thalesfsp/customerror"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// ErrReqBodyNotReplayable is returned when a request needs to be retried, but
+// its body can't be sent again, e.g.: a non-seekable `io.Reader` which was
+// already consumed by a previous attempt.
+var ErrReqBodyNotReplayable = customerror.New(
+	"request body isn't replayable, use a string, struct, url.Values, or an io.ReadSeeker",
+	customerror.WithStatusCode(http.StatusBadRequest),
+)
+
+// reqBody allows to send the same request body across multiple attempts. Bodies
+// set via `WithReqBody` as string, struct, or `url.Values` are in-memory copies,
+// hence seekable. Any other `io.Seeker` is rewound to where it was at the first
+// attempt. Bodies with `getBody`, see `DoRequest`, are got again. Anything else
+// can only be sent once.
+//
+// NOTE: Seekable bodies which are also `io.Closer`s, e.g.: `*os.File`, aren't
+// closed by `net/http`, so they can be rewound. The client closes them once
+// the last attempt is done.
+type reqBody struct {
+	// getBody returns a new copy of the body.
+	getBody func() (io.ReadCloser, error)
+
+	// reader is the original body.
+	reader io.Reader
+
+	// closer is set if `reader` is closed by the client, instead of `net/http`.
+	closer io.Closer
+
+	// seeker is set if `reader` can be rewound.
+	seeker io.Seeker
+
+	// offset is where the `seeker` was at the first attempt.
+	offset int64
+
+	// sent is true after the first attempt.
+	sent bool
+}
+
+// noCloseReadSeeker hides the `io.Closer` of a seekable body, so `net/http`
+// doesn't close it after the first attempt.
+type noCloseReadSeeker struct {
+	io.ReadSeeker
+}
+
+//////
+// Methods.
+//////
+
+// Reader returns the body to be used in the next attempt.
+func (b *reqBody) Reader() (io.Reader, error) {
+	if b == nil || b.reader == nil {
+		return nil, nil
+	}
+
+	if !b.sent {
+		b.sent = true
+
+		return b.reader, nil
+	}
+
+	if b.getBody != nil {
+		r, err := b.getBody()
+		if err != nil {
+			return nil, customerror.NewFailedToError(
+				"get request body",
+				customerror.WithError(err),
+				customerror.WithStatusCode(http.StatusBadRequest),
+			)
+		}
+
+		return r, nil
+	}
+
+	if b.seeker == nil {
+		return nil, ErrReqBodyNotReplayable
+	}
+
+	if _, err := b.seeker.Seek(b.offset, io.SeekStart); err != nil {
+		return nil, customerror.NewFailedToError(
+			"rewind request body",
+			customerror.WithError(err),
+			customerror.WithStatusCode(http.StatusBadRequest),
+		)
+	}
+
+	return b.reader, nil
+}
+
+// Close closes the body, if `net/http` doesn't.
+func (b *reqBody) Close() error {
+	if b == nil || b.closer == nil {
+		return nil
+	}
+
+	return b.closer.Close()
+}
+
+//////
+// Factory.
+//////
+
+// newReqBody wraps `r` allowing it to be replayed, if possible.
+func newReqBody(r io.Reader) *reqBody {
+	b := &reqBody{reader: r}
+
+	if s, ok := r.(io.Seeker); ok {
+		// Non-seekable files (e.g.: pipes) implement `io.Seeker`, but errors.
+		if offset, err := s.Seek(0, io.SeekCurrent); err == nil {
+			b.seeker = s
+			b.offset = offset
+
+			if rs, ok := r.(io.ReadSeekCloser); ok {
+				b.closer = rs
+				b.reader = noCloseReadSeeker{rs}
+			}
+		}
+	}
+
+	return b
+}
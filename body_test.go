@@ -0,0 +1,142 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Post_replayReqBody(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         interface{}
+		expectedBody string
+		wantErr      error
+	}{
+		{
+			name:         "string body",
+			body:         "test",
+			expectedBody: "test",
+		},
+		{
+			name:         "struct body",
+			body:         TestStruct{A: "test"},
+			expectedBody: `{"a":"test"}`,
+		},
+		{
+			name:         "url.Values body",
+			body:         url.Values{"A": {"test"}},
+			expectedBody: "A=test",
+		},
+		{
+			name:         "seekable io.Reader body",
+			body:         bytes.NewReader([]byte("test")),
+			expectedBody: "test",
+		},
+		{
+			name:         "file body",
+			body:         testFile(t, "test"),
+			expectedBody: "test",
+		},
+		{
+			name:         "non-seekable io.Reader body",
+			body:         io.MultiReader(strings.NewReader("test")),
+			expectedBody: "test",
+			wantErr:      ErrReqBodyNotReplayable,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu     sync.Mutex
+				bodies []string
+			)
+
+			// First attempt fails with a retryable status code, the second
+			// succeeds.
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+
+					return
+				}
+
+				mu.Lock()
+				bodies = append(bodies, string(b))
+				attempt := len(bodies)
+				mu.Unlock()
+
+				if attempt == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			c, err := New(fmt.Sprintf("replay%d", i), nil, 0, 100*time.Millisecond, 1)
+			assert.NoError(t, err)
+
+			resp, err := c.Post(ctx, server.URL, WithReqBody(tt.body))
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "err = %v, want %v", err, tt.wantErr)
+				assert.Len(t, bodies, 1)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, []string{tt.expectedBody, tt.expectedBody}, bodies)
+
+			// Files are closed once the last attempt is done.
+			if f, ok := tt.body.(*os.File); ok {
+				_, err := f.Stat()
+				assert.True(t, errors.Is(err, os.ErrClosed), "err = %v, want %v", err, os.ErrClosed)
+			}
+		})
+	}
+}
+
+// testFile creates a temporary file with `content`, ready to be read.
+func testFile(t *testing.T, content string) *os.File {
+	t.Helper()
+
+	f, err := os.CreateTemp(t.TempDir(), "body")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { f.Close() })
+
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
@@ -0,0 +1,213 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/customerror"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// ErrBulkheadTimeout is returned when a request waited in the bulkhead queue
+// longer than the queue timeout. It's never retried.
+var ErrBulkheadTimeout = customerror.New(
+	"bulkhead queue timeout, too many requests in-flight",
+	customerror.WithStatusCode(http.StatusServiceUnavailable),
+)
+
+// BulkheadConfig limits the number of in-flight requests, isolating a slow
+// dependency from starving the rest.
+//
+// NOTE: A slot is held per attempt, until its response body is closed, so
+// reading bodies counts as in-flight. Failed attempts release it right away.
+type BulkheadConfig struct {
+	// MaxInFlight is the max number of in-flight requests of the client. Zero
+	// means no limit.
+	MaxInFlight int
+
+	// MaxInFlightPerHost is the max number of in-flight requests per host.
+	// Zero means no limit.
+	MaxInFlightPerHost int
+
+	// QueueTimeout is the max amount of time waiting for a slot, failing with
+	// `ErrBulkheadTimeout`. Zero means waiting until the request context is
+	// done.
+	QueueTimeout time.Duration
+}
+
+// bulkhead holds the semaphores.
+type bulkhead struct {
+	config BulkheadConfig
+
+	// client is the client semaphore, nil if no limit.
+	client chan struct{}
+
+	mu    sync.Mutex
+	hosts map[string]chan struct{}
+}
+
+//////
+// Methods.
+//////
+
+// host returns the semaphore of `host`, nil if no limit.
+func (b *bulkhead) host(host string) chan struct{} {
+	if b.config.MaxInFlightPerHost <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sem, ok := b.hosts[host]
+	if !ok {
+		sem = make(chan struct{}, b.config.MaxInFlightPerHost)
+
+		b.hosts[host] = sem
+	}
+
+	return sem
+}
+
+// acquireBulkhead waits for a slot, host first, then client, returning the
+// function releasing them, once. In-flight requests are tracked even without
+// a bulkhead.
+//
+// NOTE: The host slot is taken first, so requests queued for a busy host
+// don't hold client slots, starving the other hosts.
+func (c *Client) acquireBulkhead(ctx context.Context, req *http.Request) (func(), error) {
+	if c.bulkhead == nil {
+		c.gaugeInFlight.Add(1)
+
+		var once sync.Once
+
+		return func() { once.Do(func() { c.gaugeInFlight.Add(-1) }) }, nil
+	}
+
+	var timeout <-chan time.Time
+
+	if c.bulkhead.config.QueueTimeout > 0 {
+		timer := time.NewTimer(c.bulkhead.config.QueueTimeout)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	c.gaugeQueued.Add(1)
+	defer c.gaugeQueued.Add(-1)
+
+	acquired := make([]chan struct{}, 0, 2)
+
+	release := func() {
+		for _, sem := range acquired {
+			<-sem
+		}
+	}
+
+	for _, sem := range []chan struct{}{c.bulkhead.host(req.URL.Host), c.bulkhead.client} {
+		if sem == nil {
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+			acquired = append(acquired, sem)
+		case <-timeout:
+			release()
+
+			c.counterBulkheadRejected.Add(1)
+
+			return nil, customerror.NewFailedToError(
+				fmt.Sprintf("send request to %s", req.URL.Host),
+				customerror.WithStatusCode(http.StatusServiceUnavailable),
+				customerror.WithError(ErrBulkheadTimeout),
+			)
+		case <-ctx.Done():
+			release()
+
+			c.counterBulkheadRejected.Add(1)
+
+			return nil, ctx.Err()
+		}
+	}
+
+	// A slot, and the context being done, may be ready at once, e.g.: a losing
+	// hedge, which shouldn't be sent.
+	if err := ctx.Err(); err != nil {
+		release()
+
+		c.counterBulkheadRejected.Add(1)
+
+		return nil, err
+	}
+
+	c.gaugeInFlight.Add(1)
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			c.gaugeInFlight.Add(-1)
+
+			release()
+		})
+	}, nil
+}
+
+// releaseOnClose makes closing the `resp` body call `release`, so the slot is
+// held while the body is read. If there's no body to be closed, it's called
+// right away.
+func releaseOnClose(resp *http.Response, err error, release func()) *http.Response {
+	if err != nil || resp == nil || resp.Body == nil || resp.Body == http.NoBody {
+		release()
+
+		return resp
+	}
+
+	resp.Body = &releaseOnCloseBody{ReadCloser: resp.Body, release: release}
+
+	return resp
+}
+
+// releaseOnCloseBody is a body which releases its slot when closed.
+type releaseOnCloseBody struct {
+	io.ReadCloser
+
+	release func()
+}
+
+// Close implements the io.Closer interface.
+func (b *releaseOnCloseBody) Close() error {
+	defer b.release()
+
+	return b.ReadCloser.Close()
+}
+
+//////
+// Factory.
+//////
+
+// newBulkhead creates a bulkhead based on `config`.
+func newBulkhead(config BulkheadConfig) (*bulkhead, error) {
+	if config.MaxInFlight < 0 || config.MaxInFlightPerHost < 0 || config.QueueTimeout < 0 {
+		return nil, customerror.NewInvalidError("bulkhead config, values can't be negative")
+	}
+
+	b := &bulkhead{
+		config: config,
+		hosts:  make(map[string]chan struct{}),
+	}
+
+	if config.MaxInFlight > 0 {
+		b.client = make(chan struct{}, config.MaxInFlight)
+	}
+
+	return b, nil
+}
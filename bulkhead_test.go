@@ -0,0 +1,297 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Get_bulkhead(t *testing.T) {
+	unblock := make(chan struct{})
+
+	blockingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer blockingServer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		clientName string
+		config     BulkheadConfig
+		url        string
+		wantErr    bool
+	}{
+		{
+			name:       "should fail - client limit reached",
+			clientName: "bulkheadclient",
+			config:     BulkheadConfig{MaxInFlight: 2, QueueTimeout: 100 * time.Millisecond},
+			url:        server.URL,
+			wantErr:    true,
+		},
+		{
+			name:       "should fail - host limit reached",
+			clientName: "bulkheadhost",
+			config:     BulkheadConfig{MaxInFlightPerHost: 2, QueueTimeout: 100 * time.Millisecond},
+			url:        blockingServer.URL,
+			wantErr:    true,
+		},
+		{
+			name:       "should work - other host isn't affected",
+			clientName: "bulkheadotherhost",
+			config:     BulkheadConfig{MaxInFlightPerHost: 2, QueueTimeout: 100 * time.Millisecond},
+			url:        server.URL,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			c, err := Initialize(WithClientName(tt.clientName), WithClientBulkhead(tt.config))
+			assert.NoError(t, err)
+
+			unblock = make(chan struct{})
+
+			var wg sync.WaitGroup
+
+			// Fills the slots.
+			for i := 0; i < 2; i++ {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					resp, err := c.Get(ctx, blockingServer.URL)
+					if assert.NoError(t, err) {
+						assert.NoError(t, resp.Body.Close())
+					}
+				}()
+			}
+
+			assert.Eventually(t, func() bool {
+				return c.gaugeInFlight.Value() == 2
+			}, time.Second, 10*time.Millisecond)
+
+			now := time.Now()
+
+			//nolint:bodyclose
+			_, err = c.Get(ctx, tt.url)
+
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrBulkheadTimeout))
+				assert.GreaterOrEqual(t, time.Since(now), 100*time.Millisecond)
+				assert.Equal(t, int64(1), c.counterBulkheadRejected.Value())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			close(unblock)
+
+			wg.Wait()
+
+			assert.Equal(t, int64(0), c.gaugeInFlight.Value())
+			assert.Equal(t, int64(0), c.gaugeQueued.Value())
+		})
+	}
+}
+
+func TestClient_Get_bulkheadClientAndHost(t *testing.T) {
+	unblock := make(chan struct{})
+
+	blockingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer blockingServer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Initialize(
+		WithClientName("bulkheadclientandhost"),
+		WithClientBulkhead(BulkheadConfig{MaxInFlight: 3, MaxInFlightPerHost: 1}),
+	)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	// One in-flight, and two queued for the slow host.
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			resp, err := c.Get(ctx, blockingServer.URL)
+			if assert.NoError(t, err) {
+				assert.NoError(t, resp.Body.Close())
+			}
+		}()
+	}
+
+	assert.Eventually(t, func() bool {
+		return c.gaugeInFlight.Value() == 1 && c.gaugeQueued.Value() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// Requests queued for the slow host don't hold client slots.
+	reqCtx, reqCancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer reqCancel()
+
+	resp, err := c.Get(reqCtx, server.URL)
+	if assert.NoError(t, err) {
+		assert.NoError(t, resp.Body.Close())
+	}
+
+	close(unblock)
+
+	wg.Wait()
+
+	assert.Equal(t, int64(0), c.gaugeInFlight.Value())
+	assert.Equal(t, int64(0), c.gaugeQueued.Value())
+	assert.Equal(t, int64(0), c.counterBulkheadRejected.Value())
+}
+
+func TestClient_Get_bulkheadCanceled(t *testing.T) {
+	unblock := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := Initialize(
+		WithClientName("bulkheadcanceled"),
+		WithClientBulkhead(BulkheadConfig{MaxInFlight: 1}),
+	)
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		resp, err := c.Get(context.Background(), server.URL)
+		if assert.NoError(t, err) {
+			assert.NoError(t, resp.Body.Close())
+		}
+	}()
+
+	assert.Eventually(t, func() bool {
+		return c.gaugeInFlight.Value() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	//nolint:bodyclose
+	_, err = c.Get(ctx, server.URL)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	// A canceled wait isn't a server failure, so it isn't retried.
+	assert.Equal(t, int64(0), c.counterRetried.Value())
+
+	close(unblock)
+
+	<-done
+}
+
+func TestClient_Get_bulkheadHeldUntilBodyClosed(t *testing.T) {
+	var hits atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Only the first hit is slow, so it's hedged.
+		if r.URL.Path == "/hedged" && hits.Add(1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+
+		w.WriteHeader(http.StatusOK)
+
+		//nolint:errcheck
+		w.Write([]byte("body"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		clientName string
+		path       string
+		opts       []Func
+	}{
+		{
+			name:       "should work",
+			clientName: "bulkheadbody",
+			path:       "/",
+		},
+		{
+			name:       "should work - hedged",
+			clientName: "bulkheadbodyhedged",
+			path:       "/hedged",
+			opts:       []Func{WithHedging(20*time.Millisecond, 2)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Initialize(
+				WithClientName(tt.clientName),
+				WithClientBulkhead(BulkheadConfig{MaxInFlight: 2, QueueTimeout: 100 * time.Millisecond}),
+			)
+			assert.NoError(t, err)
+
+			resp, err := c.Get(context.Background(), server.URL+tt.path, tt.opts...)
+			assert.NoError(t, err)
+
+			// The losing hedge, if any, is released once drained.
+			assert.Eventually(t, func() bool {
+				return c.gaugeInFlight.Value() == 1
+			}, time.Second, 10*time.Millisecond)
+
+			// The unread body holds its slot.
+			other, err := c.Get(context.Background(), server.URL)
+			assert.NoError(t, err)
+
+			//nolint:bodyclose
+			_, err = c.Get(context.Background(), server.URL)
+			assert.True(t, errors.Is(err, ErrBulkheadTimeout))
+
+			assert.NoError(t, resp.Body.Close())
+			assert.NoError(t, other.Body.Close())
+
+			assert.Equal(t, int64(0), c.gaugeInFlight.Value())
+
+			resp, err = c.Get(context.Background(), server.URL)
+			assert.NoError(t, err)
+			assert.NoError(t, resp.Body.Close())
+		})
+	}
+}
+
+func TestInitialize_invalidBulkhead(t *testing.T) {
+	_, err := Initialize(
+		WithClientName("bulkheadinvalid"),
+		WithClientBulkhead(BulkheadConfig{MaxInFlight: -1}),
+	)
+	assert.Error(t, err)
+}
@@ -0,0 +1,275 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/eapache/go-resiliency/breaker"
+	"github.com/thalesfsp/customerror"
+	"github.com/thalesfsp/sypl"
+	"github.com/thalesfsp/sypl/level"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// Circuit breaker states.
+const (
+	CircuitClosed   = "closed"
+	CircuitHalfOpen = "half-open"
+	CircuitOpen     = "open"
+)
+
+// ErrCircuitOpen is returned, without touching the network, when the circuit
+// breaker of the host is open. It's never retried.
+var ErrCircuitOpen = customerror.New(
+	"circuit breaker is open",
+	customerror.WithStatusCode(http.StatusServiceUnavailable),
+	customerror.WithError(breaker.ErrBreakerOpen),
+)
+
+// errCircuitFailure signals a server failure to the breaker.
+var errCircuitFailure = errors.New("server failure")
+
+// CircuitBreakerConfig configures the per host circuit breaker.
+//
+// From closed, it opens if `ErrorThreshold` failures are seen without a
+// failure-free period of at least `Timeout`. From open, it half-opens after
+// `Timeout`. From half-open, it closes after `SuccessThreshold` consecutive
+// successes, or opens on a single failure.
+//
+// NOTE: Failures are transport errors, and `5xx` responses. Every attempt,
+// retries included, counts.
+type CircuitBreakerConfig struct {
+	// ErrorThreshold is the number of failures opening the breaker. Defaults
+	// to 5.
+	ErrorThreshold int
+
+	// SuccessThreshold is the number of successes closing the half-open
+	// breaker. Defaults to 1.
+	SuccessThreshold int
+
+	// Timeout is how long the breaker stays open. Defaults to 30s.
+	Timeout time.Duration
+}
+
+// hostBreaker is the breaker of a host. Transitions are tracked as observed
+// by requests, as `breaker.Breaker` doesn't expose them, see `begin`, and
+// `observe`.
+type hostBreaker struct {
+	breaker *breaker.Breaker
+
+	mu        sync.Mutex
+	state     string
+	successes int
+}
+
+// circuitBreakers holds the breakers, per host.
+type circuitBreakers struct {
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex
+	breakers map[string]*hostBreaker
+}
+
+//////
+// Methods.
+//////
+
+// get returns the breaker of `host`, creating it if needed.
+func (cb *circuitBreakers) get(host string) (*hostBreaker, bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	hb, ok := cb.breakers[host]
+	if !ok {
+		hb = &hostBreaker{
+			breaker: breaker.New(cb.config.ErrorThreshold, cb.config.SuccessThreshold, cb.config.Timeout),
+			state:   CircuitClosed,
+		}
+
+		cb.breakers[host] = hb
+	}
+
+	return hb, !ok
+}
+
+// transition moves to `state`, appending it to `states`, if it changed.
+func (hb *hostBreaker) transition(states []string, state string) []string {
+	if state == hb.state {
+		return states
+	}
+
+	hb.state = state
+	hb.successes = 0
+
+	return append(states, state)
+}
+
+// begin tracks the start of a `Run` which work is running, hence the breaker
+// isn't open anymore. It returns the state the run started in, and the states
+// it went through, the previous one first.
+func (hb *hostBreaker) begin() (string, []string) {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+
+	states := []string{hb.state}
+
+	// Only a run started after the timeout is a half-open trial. Runs started
+	// before it was open can't move it.
+	if hb.state == CircuitOpen {
+		states = hb.transition(states, CircuitHalfOpen)
+	}
+
+	return hb.state, states
+}
+
+// observe tracks the breaker state after a `Run`, started in `started`, see
+// `begin`, resulting in `err`. It returns the states it went through, the
+// previous one first. It mirrors `breaker.Breaker`: successes only count for
+// runs started half-open, failures count against the current state.
+func (hb *hostBreaker) observe(started string, err error, successThreshold int) []string {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+
+	states := []string{hb.state}
+
+	switch {
+	case errors.Is(err, breaker.ErrBreakerOpen):
+		return hb.transition(states, CircuitOpen)
+	case err == nil && started == CircuitHalfOpen && hb.state == CircuitHalfOpen:
+		hb.successes++
+
+		if hb.successes >= successThreshold {
+			states = hb.transition(states, CircuitClosed)
+		}
+	case err != nil && hb.state == CircuitHalfOpen:
+		states = hb.transition(states, CircuitOpen)
+	case err != nil && hb.state == CircuitClosed:
+		// A successful no-op tells if the failure opened it, without changing
+		// the breaker state.
+		if errors.Is(hb.breaker.Run(func() error { return nil }), breaker.ErrBreakerOpen) {
+			states = hb.transition(states, CircuitOpen)
+		}
+	}
+
+	return states
+}
+
+// reportCircuitTransitions records the `states` the breaker of `host` went
+// through, the previous one first, in logs, and metrics.
+func (c *Client) reportCircuitTransitions(host string, states []string) {
+	for i := 1; i < len(states); i++ {
+		c.counterCircuitTransitions.Add(1)
+		c.circuitBreakerState.Set(host, stringVar(states[i]))
+
+		c.GetLogger().PrintlnWithOptions(
+			level.Warn,
+			fmt.Sprintf("circuit breaker of %s changed from %s to %s", host, states[i-1], states[i]),
+			sypl.WithTags("request", "circuitbreaker"),
+		)
+	}
+}
+
+// runCircuitBreaker runs `work` through the breaker of the `req` host, if
+// any. Failures are transport errors, except cancellations, and `5xx`
+// responses.
+func (c *Client) runCircuitBreaker(
+	ctx context.Context,
+	req *http.Request,
+	work func() (*http.Response, error),
+) (*http.Response, error) {
+	if c.circuitBreakers == nil {
+		return work()
+	}
+
+	host := req.URL.Host
+
+	hb, created := c.circuitBreakers.get(host)
+	if created {
+		c.circuitBreakerState.Set(host, stringVar(CircuitClosed))
+	}
+
+	var (
+		resp *http.Response
+		err  error
+	)
+
+	var started string
+
+	runErr := hb.breaker.Run(func() error {
+		var states []string
+
+		started, states = hb.begin()
+
+		c.reportCircuitTransitions(host, states)
+
+		resp, err = work()
+
+		switch {
+		case err != nil && ctx.Err() == nil:
+			return err
+		case err == nil && resp.StatusCode >= http.StatusInternalServerError:
+			return errCircuitFailure
+		default:
+			return nil
+		}
+	})
+
+	c.reportCircuitTransitions(host, hb.observe(started, runErr, c.circuitBreakers.config.SuccessThreshold))
+
+	if errors.Is(runErr, breaker.ErrBreakerOpen) {
+		c.counterCircuitRejected.Add(1)
+
+		return nil, customerror.NewFailedToError(
+			fmt.Sprintf("send request to %s", host),
+			customerror.WithStatusCode(http.StatusServiceUnavailable),
+			customerror.WithError(ErrCircuitOpen),
+		)
+	}
+
+	return resp, err
+}
+
+// stringVar creates an expvar string set to `s`.
+func stringVar(s string) *expvar.String {
+	v := new(expvar.String)
+
+	v.Set(s)
+
+	return v
+}
+
+//////
+// Factory.
+//////
+
+// newCircuitBreakers creates the per host breakers based on `config`.
+func newCircuitBreakers(config CircuitBreakerConfig) (*circuitBreakers, error) {
+	if config.ErrorThreshold < 0 || config.SuccessThreshold < 0 || config.Timeout < 0 {
+		return nil, customerror.NewInvalidError("circuit breaker config, values can't be negative")
+	}
+
+	if config.ErrorThreshold == 0 {
+		config.ErrorThreshold = 5
+	}
+
+	if config.SuccessThreshold == 0 {
+		config.SuccessThreshold = 1
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &circuitBreakers{
+		breakers: make(map[string]*hostBreaker),
+		config:   config,
+	}, nil
+}
@@ -0,0 +1,167 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Get_circuitBreaker(t *testing.T) {
+	var (
+		hits    int32
+		failing int32 = 1
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// A healthy host, not affected by the other one.
+	otherServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer otherServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Initialize(
+		WithClientName("circuitbreaker"),
+		WithClientRetrier(100*time.Millisecond, 3),
+		WithClientBackoff(ConstantBackoff(100*time.Millisecond)),
+		WithClientCircuitBreaker(CircuitBreakerConfig{
+			ErrorThreshold:   2,
+			SuccessThreshold: 1,
+			Timeout:          500 * time.Millisecond,
+		}),
+	)
+	assert.NoError(t, err)
+
+	// Opens after 2 failures, the remaining retry isn't tried.
+	//nolint:bodyclose
+	_, err = c.Get(ctx, server.URL)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+
+	// Fails without touching the network.
+	//nolint:bodyclose
+	_, err = c.Get(ctx, server.URL)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+
+	resp, err := c.Get(ctx, otherServer.URL)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	// Half-opens after the timeout, and closes on success.
+	atomic.StoreInt32(&failing, 0)
+
+	time.Sleep(600 * time.Millisecond)
+
+	resp, err = c.Get(ctx, server.URL)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+
+	// closed -> open -> half-open -> closed.
+	assert.Equal(t, int64(3), c.counterCircuitTransitions.Value())
+	assert.Equal(t, int64(2), c.counterCircuitRejected.Value())
+	assert.Equal(t, CircuitClosed, c.circuitBreakerState.Get(server.Listener.Addr().String()).(interface{ Value() string }).Value())
+}
+
+func TestClient_Get_circuitBreakerIgnores4xx(t *testing.T) {
+	var hits int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Initialize(
+		WithClientName("circuitbreaker4xx"),
+		WithClientCircuitBreaker(CircuitBreakerConfig{ErrorThreshold: 1}),
+	)
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		//nolint:bodyclose
+		_, err = c.Get(ctx, server.URL)
+		assert.True(t, IsNotFound(err))
+	}
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+}
+
+func TestClient_Get_circuitBreakerConcurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			time.Sleep(50 * time.Millisecond)
+
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		time.Sleep(150 * time.Millisecond)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Initialize(
+		WithClientName("circuitbreakerconcurrent"),
+		WithClientRetryPolicy(NeverRetryPolicy),
+		WithClientCircuitBreaker(CircuitBreakerConfig{ErrorThreshold: 1, Timeout: time.Minute}),
+	)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	// A slow failure, and a slower success, both started closed.
+	for _, path := range []string{"/fail", "/ok"} {
+		wg.Add(1)
+
+		go func(path string) {
+			defer wg.Done()
+
+			resp, err := c.Get(ctx, server.URL+path)
+			if err == nil {
+				assert.NoError(t, resp.Body.Close())
+			}
+		}(path)
+	}
+
+	wg.Wait()
+
+	// The success, started before it opened, doesn't move it.
+	assert.Equal(t, int64(1), c.counterCircuitTransitions.Value())
+	assert.Equal(t, CircuitOpen, c.Metrics().CircuitBreakerStates[server.Listener.Addr().String()])
+
+	//nolint:bodyclose
+	_, err = c.Get(ctx, server.URL+"/ok")
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+}
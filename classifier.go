@@ -11,6 +11,7 @@ import (
 
 // HTTPStatusCodeClassifier classifies errors based on a HTTP status code, or
 // a regex. It will automatically fail if error isn't of the type `CustomError`.
+// It's also a `RetryPolicy`.
 type HTTPStatusCodeClassifier struct {
 	Regex       *regexp.Regexp
 	StatusCodes []int
@@ -44,3 +45,8 @@ func (hSCC HTTPStatusCodeClassifier) Classify(err error) retrier.Action {
 	// Should fail if it isn't of the type `CustomError`, and everything else.
 	return retrier.Fail
 }
+
+// ShouldRetry implements the RetryPolicy interface.
+func (hSCC HTTPStatusCodeClassifier) ShouldRetry(attempt *Attempt) bool {
+	return hSCC.Classify(attempt.Error) == retrier.Retry
+}
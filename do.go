@@ -0,0 +1,68 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/thalesfsp/customerror"
+)
+
+// Do does a request with any `method`, e.g.: `TRACE`, or custom ones.
+//
+// NOTE: If `opt.RespBody` is provided, it will read and decode the body, ALSO
+// CLOSING IT. Otherwise, the body will be left open, and returned. In this case
+// IT'S THE CALLER'S RESPONSIBILITY TO CLOSE THE BODY.
+func (c *Client) Do(
+	ctx context.Context,
+	method string,
+	url string,
+	o ...Func,
+) (*http.Response, error) {
+	return c.request(
+		ctx,
+		method,
+		url,
+		o...,
+	)
+}
+
+// DoRequest does a caller-built request, through the same pipeline: base URL,
+// default headers, middlewares, retries, logging, and metrics. Its context,
+// headers, host, and body are kept. Options are applied on top of it.
+//
+// NOTE: Retries resend the body using `req.GetBody`, set by `http.NewRequest`
+// for in-memory bodies. Without it, it fails with `ErrReqBodyNotReplayable` if
+// a retry is needed.
+//
+// NOTE: If `opt.RespBody` is provided, it will read and decode the body, ALSO
+// CLOSING IT. Otherwise, the body will be left open, and returned. In this case
+// IT'S THE CALLER'S RESPONSIBILITY TO CLOSE THE BODY.
+func (c *Client) DoRequest(req *http.Request, o ...Func) (*http.Response, error) {
+	if req == nil || req.URL == nil {
+		return nil, customerror.NewRequiredError("request is")
+	}
+
+	opts := make([]Func, 0, len(o)+1)
+	opts = append(opts, withRequest(req))
+	opts = append(opts, o...)
+
+	return c.request(
+		req.Context(),
+		req.Method,
+		req.URL.String(),
+		opts...,
+	)
+}
+
+// withRequest bases the request on `req`, see `DoRequest`.
+func withRequest(req *http.Request) Func {
+	return func(o *Options) error {
+		o.baseReq = req
+
+		if req.Body != nil && req.Body != http.NoBody {
+			o.reqBodyAsIOReader = req.Body
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,177 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_verbs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Method", r.Method)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var c IHTTP
+
+	c, err := Initialize(WithClientName("verbs"), WithClientBaseURL(server.URL))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		do     func(ctx context.Context) (*http.Response, error)
+		method string
+	}{
+		{
+			name: "Patch",
+			do: func(ctx context.Context) (*http.Response, error) {
+				return c.Patch(ctx, "/")
+			},
+			method: http.MethodPatch,
+		},
+		{
+			name: "Head",
+			do: func(ctx context.Context) (*http.Response, error) {
+				return c.Head(ctx, "/")
+			},
+			method: http.MethodHead,
+		},
+		{
+			name: "Options",
+			do: func(ctx context.Context) (*http.Response, error) {
+				return c.Options(ctx, "/")
+			},
+			method: http.MethodOptions,
+		},
+		{
+			name: "Do",
+			do: func(ctx context.Context) (*http.Response, error) {
+				return c.Do(ctx, http.MethodTrace, "/")
+			},
+			method: http.MethodTrace,
+		},
+		{
+			name: "DoRequest",
+			do: func(ctx context.Context) (*http.Response, error) {
+				req, err := http.NewRequestWithContext(ctx, "PURGE", server.URL, nil)
+				if err != nil {
+					return nil, err
+				}
+
+				return c.DoRequest(req)
+			},
+			method: "PURGE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			resp, err := tt.do(ctx)
+			assert.NoError(t, err)
+
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.method, resp.Header.Get("X-Method"))
+		})
+	}
+}
+
+func TestClient_DoRequest(t *testing.T) {
+	tests := []struct {
+		name             string
+		clientName       string
+		newBody          func() io.Reader
+		expectedAttempts int32
+		wantErr          error
+	}{
+		{
+			name:             "should work - body replayed via GetBody",
+			clientName:       "dorequestgetbody",
+			newBody:          func() io.Reader { return bytes.NewBufferString("data") },
+			expectedAttempts: 2,
+		},
+		{
+			name:       "should fail - body without GetBody",
+			clientName: "dorequestnogetbody",
+			newBody: func() io.Reader {
+				return io.NopCloser(strings.NewReader("data"))
+			},
+			expectedAttempts: 1,
+			wantErr:          ErrReqBodyNotReplayable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+
+			// First attempt fails, the second succeeds, echoing the request.
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+
+					return
+				}
+
+				if atomic.AddInt32(&attempts, 1) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+
+					return
+				}
+
+				if _, err := w.Write([]byte(r.Header.Get("X-Custom") + ":" + string(body))); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+				}
+			}))
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			c, err := Initialize(
+				WithClientName(tt.clientName),
+				WithClientRetrier(100*time.Millisecond, 1),
+			)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, tt.newBody())
+			assert.NoError(t, err)
+
+			req.Header.Set("X-Custom", "custom")
+
+			resp, err := c.DoRequest(req)
+
+			assert.Equal(t, tt.expectedAttempts, atomic.LoadInt32(&attempts))
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "custom:data", readBody(t, resp))
+		})
+	}
+
+	t.Run("should fail - nil request", func(t *testing.T) {
+		c, err := Initialize(WithClientName("dorequestnil"))
+		assert.NoError(t, err)
+
+		//nolint:bodyclose
+		_, err = c.DoRequest(nil)
+		assert.Error(t, err)
+	})
+}
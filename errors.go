@@ -0,0 +1,199 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// RetryRecord describes a failed attempt which was retried.
+type RetryRecord struct {
+	// Attempt is the attempt number, starting at 1.
+	Attempt int
+
+	// Error is the attempt's error.
+	Error error
+
+	// StatusCode is the attempt's response status code. It's zero for
+	// transport errors.
+	StatusCode int
+
+	// Wait is how long it waited before the next attempt.
+	Wait time.Duration
+}
+
+// HTTPError is the error of a request which got a non-2xx response. Use
+// `errors.As` to get it.
+//
+// NOTE: It wraps the `customerror.CustomError` describing the failure.
+type HTTPError struct {
+	// Attempts is the number of attempts made, first one included.
+	Attempts int
+
+	// Body is the raw response body.
+	Body []byte
+
+	// Header is the response headers.
+	Header http.Header
+
+	// Method is the request's HTTP method.
+	Method string
+
+	// Retries is the retry history, oldest first.
+	Retries []RetryRecord
+
+	// StatusCode is the response status code.
+	StatusCode int
+
+	// URL is the request's URL.
+	URL string
+
+	err error
+}
+
+//////
+// Methods.
+//////
+
+// Error implements the error interface.
+func (e *HTTPError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *HTTPError) Unwrap() error {
+	return e.err
+}
+
+// isStatusError returns true if `statusCode` should be turned into an error.
+// By default, anything but 2xx, and 3xx. It's configurable per-client, and
+// per-request.
+func (c *Client) isStatusError(statusCode int, o *Options) bool {
+	if o.NoStatusError {
+		return false
+	}
+
+	if len(o.ExpectedStatus) > 0 {
+		for _, expected := range o.ExpectedStatus {
+			if statusCode == expected {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if statusCode < http.StatusOK || statusCode >= http.StatusBadRequest {
+		return true
+	}
+
+	redirectIsError := c.RedirectIsError
+
+	if o.RedirectIsError != nil {
+		redirectIsError = *o.RedirectIsError
+	}
+
+	return redirectIsError && statusCode >= http.StatusMultipleChoices
+}
+
+//////
+// Factory.
+//////
+
+// newHTTPError creates a `HTTPError` based on `resp`, wrapping `err`.
+func newHTTPError(method, url string, resp *http.Response, body []byte, err error) *HTTPError {
+	return &HTTPError{
+		Attempts:   1,
+		Body:       body,
+		Header:     resp.Header,
+		Method:     method,
+		StatusCode: resp.StatusCode,
+		URL:        url,
+
+		err: err,
+	}
+}
+
+//////
+// Helpers.
+//////
+
+// IsStatus returns true if `err` is a `HTTPError` with any of the
+// `statusCodes`.
+func IsStatus(err error, statusCodes ...int) bool {
+	var httpErr *HTTPError
+
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+
+	for _, statusCode := range statusCodes {
+		if httpErr.StatusCode == statusCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsBadRequest returns true if `err` is a `400` `HTTPError`.
+func IsBadRequest(err error) bool {
+	return IsStatus(err, http.StatusBadRequest)
+}
+
+// IsUnauthorized returns true if `err` is a `401` `HTTPError`.
+func IsUnauthorized(err error) bool {
+	return IsStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden returns true if `err` is a `403` `HTTPError`.
+func IsForbidden(err error) bool {
+	return IsStatus(err, http.StatusForbidden)
+}
+
+// IsNotFound returns true if `err` is a `404` `HTTPError`.
+func IsNotFound(err error) bool {
+	return IsStatus(err, http.StatusNotFound)
+}
+
+// IsConflict returns true if `err` is a `409` `HTTPError`.
+func IsConflict(err error) bool {
+	return IsStatus(err, http.StatusConflict)
+}
+
+// IsTooManyRequests returns true if `err` is a `429` `HTTPError`.
+func IsTooManyRequests(err error) bool {
+	return IsStatus(err, http.StatusTooManyRequests)
+}
+
+// IsServerError returns true if `err` is a `5xx` `HTTPError`.
+func IsServerError(err error) bool {
+	var httpErr *HTTPError
+
+	return errors.As(err, &httpErr) &&
+		httpErr.StatusCode >= http.StatusInternalServerError &&
+		httpErr.StatusCode < 600
+}
+
+// IsRetryable returns true if `err` is worth retrying according to the
+// `DefaultRetryPolicy`, or the `TransientErrorRetryPolicy`, e.g.: `429`,
+// `5xx`, timeouts, and connection resets.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	attempt := &Attempt{Error: err}
+
+	var httpErr *HTTPError
+
+	if errors.As(err, &httpErr) {
+		return DefaultRetryPolicy.ShouldRetry(attempt)
+	}
+
+	return TransientErrorRetryPolicy.ShouldRetry(attempt)
+}
@@ -0,0 +1,256 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/customerror"
+)
+
+func TestClient_Get_httpError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/notfound":
+			w.Header().Set("X-Request-Id", "1")
+			w.WriteHeader(http.StatusNotFound)
+
+			if _, err := w.Write([]byte(`{"message":"not found"}`)); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		case "/conflict":
+			w.WriteHeader(http.StatusConflict)
+		case "/unauthorized":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	c, err := Initialize(
+		WithClientName("httperror"),
+		WithClientBaseURL(server.URL),
+		WithClientRetrier(100*time.Millisecond, 2),
+		WithClientBackoff(ConstantBackoff(100*time.Millisecond)),
+	)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		path             string
+		expectedStatus   int
+		expectedAttempts int
+		expectedBody     string
+		check            func(err error) bool
+		retryable        bool
+	}{
+		{
+			name:             "404",
+			path:             "/notfound",
+			expectedStatus:   http.StatusNotFound,
+			expectedAttempts: 1,
+			expectedBody:     `{"message":"not found"}`,
+			check:            IsNotFound,
+		},
+		{
+			name:             "409",
+			path:             "/conflict",
+			expectedStatus:   http.StatusConflict,
+			expectedAttempts: 1,
+			check:            IsConflict,
+		},
+		{
+			name:             "401",
+			path:             "/unauthorized",
+			expectedStatus:   http.StatusUnauthorized,
+			expectedAttempts: 1,
+			check:            IsUnauthorized,
+		},
+		{
+			name:             "503 - retried",
+			path:             "/unavailable",
+			expectedStatus:   http.StatusServiceUnavailable,
+			expectedAttempts: 3,
+			check:            IsServerError,
+			retryable:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			_, err := c.Get(ctx, tt.path)
+			assert.Error(t, err)
+
+			var httpErr *HTTPError
+
+			assert.True(t, errors.As(err, &httpErr))
+			assert.Equal(t, tt.expectedStatus, httpErr.StatusCode)
+			assert.Equal(t, http.MethodGet, httpErr.Method)
+			assert.Equal(t, server.URL+tt.path, httpErr.URL)
+			assert.Equal(t, tt.expectedAttempts, httpErr.Attempts)
+			assert.Len(t, httpErr.Retries, tt.expectedAttempts-1)
+			assert.True(t, tt.check(err))
+			assert.Equal(t, tt.retryable, IsRetryable(err))
+
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, string(httpErr.Body))
+				assert.Equal(t, "1", httpErr.Header.Get("X-Request-Id"))
+			}
+
+			for i, record := range httpErr.Retries {
+				assert.Equal(t, i+1, record.Attempt)
+				assert.Equal(t, tt.expectedStatus, record.StatusCode)
+				assert.Equal(t, 100*time.Millisecond, record.Wait)
+				assert.Error(t, record.Error)
+			}
+
+			// Still a `CustomError`.
+			var cE *customerror.CustomError
+
+			assert.True(t, errors.As(err, &cE))
+			assert.Equal(t, tt.expectedStatus, cE.StatusCode)
+		})
+	}
+}
+
+func TestIsStatus(t *testing.T) {
+	err := &HTTPError{StatusCode: http.StatusNotFound, err: customerror.NewHTTPError(http.StatusNotFound)}
+
+	assert.True(t, IsStatus(err, http.StatusConflict, http.StatusNotFound))
+	assert.False(t, IsStatus(err, http.StatusConflict))
+	assert.False(t, IsStatus(errors.New("not found"), http.StatusNotFound))
+	assert.False(t, IsNotFound(nil))
+	assert.False(t, IsRetryable(nil))
+}
+
+func TestClient_Get_expectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			if _, err := w.Write([]byte(`{"id":"1"}`)); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		case "/notmodified":
+			w.WriteHeader(http.StatusNotModified)
+		case "/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+
+			if _, err := w.Write([]byte(`{"code":"not_found","message":"item not found"}`)); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		}
+	}))
+	defer server.Close()
+
+	c, err := Initialize(
+		WithClientName("expectedstatus"),
+		WithClientBaseURL(server.URL),
+		WithClientRetryPolicy(NeverRetryPolicy),
+	)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		path           string
+		opts           []Func
+		expectedStatus int
+		expectedBody   string
+		wantErr        bool
+	}{
+		{
+			name:           "should work - expected 404, body intact",
+			path:           "/missing",
+			opts:           []Func{WithExpectedStatus(http.StatusOK, http.StatusNotFound)},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"code":"not_found","message":"item not found"}`,
+		},
+		{
+			name:           "should work - expected 404, error body decoded, body intact",
+			path:           "/missing",
+			opts:           []Func{WithExpectedStatus(http.StatusNotFound), WithErrBody(&testJSONError{})},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"code":"not_found","message":"item not found"}`,
+		},
+		{
+			name:    "should fail - not expected 200",
+			path:    "/ok",
+			opts:    []Func{WithExpectedStatus(http.StatusNotFound)},
+			wantErr: true,
+		},
+		{
+			name:           "should work - no status error",
+			path:           "/unavailable",
+			opts:           []Func{WithNoStatusError()},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "should work - 3xx isn't an error by default",
+			path:           "/notmodified",
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:    "should fail - 3xx is an error",
+			path:    "/notmodified",
+			opts:    []Func{WithRedirectIsError(true)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			resp, err := c.Get(ctx, tt.path, tt.opts...)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedBody, readBody(t, resp))
+		})
+	}
+
+	t.Run("error body target", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var apiErr testJSONError
+
+		resp, err := c.Get(ctx, "/missing", WithExpectedStatus(http.StatusNotFound), WithErrBody(&apiErr))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, testJSONError{Code: "not_found", Message: "item not found"}, apiErr)
+	})
+
+	t.Run("client wide 3xx is an error", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		redirectClient, err := Initialize(
+			WithClientName("expectedstatusredirect"),
+			WithClientBaseURL(server.URL),
+			WithClientRedirectIsError(true),
+		)
+		assert.NoError(t, err)
+
+		_, err = redirectClient.Get(ctx, "/notmodified")
+		assert.True(t, IsStatus(err, http.StatusNotModified))
+
+		// Per-request override.
+		resp, err := redirectClient.Get(ctx, "/notmodified", WithRedirectIsError(false))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	})
+}
@@ -0,0 +1,22 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+)
+
+// Head does a `HEAD` request.
+//
+// NOTE: The response has no body, but it still must be closed.
+func (c *Client) Head(
+	ctx context.Context,
+	url string,
+	o ...Func,
+) (*http.Response, error) {
+	return c.request(
+		ctx,
+		http.MethodHead,
+		url,
+		o...,
+	)
+}
@@ -0,0 +1,205 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// HedgingConfig configures hedged requests: if an attempt hasn't answered
+// after `Delay`, another one is fired, up to `MaxAttempts`. The first
+// successful response wins, the others are canceled.
+type HedgingConfig struct {
+	// AnyMethod allows hedging non-idempotent methods, e.g.: `POST`.
+	AnyMethod bool
+
+	// Delay is how long to wait before firing the next attempt.
+	Delay time.Duration
+
+	// MaxAttempts is the max number of concurrent attempts, first one
+	// included.
+	MaxAttempts int
+}
+
+// heldRoundTripFunc is a `RoundTripFunc` which request already holds a slot,
+// released by `release` once the response is done with, see `releaseOnClose`.
+type heldRoundTripFunc func(req *http.Request, release func()) (*http.Response, error)
+
+// gateFunc waits for what limits an attempt, e.g.: the rate limiter, and the
+// bulkhead, returning the function releasing it.
+type gateFunc func(req *http.Request) (func(), error)
+
+// hedgeResult is the result of a hedged attempt.
+type hedgeResult struct {
+	cancel context.CancelFunc
+	err    error
+	number int
+	resp   *http.Response
+}
+
+//////
+// Methods.
+//////
+
+// isSuccess returns true if the hedged attempt is a success: no error, and
+// not a server failure.
+func (r hedgeResult) isSuccess() bool {
+	return r.err == nil && r.resp.StatusCode < http.StatusInternalServerError
+}
+
+// discard cancels the hedged attempt, closing its body, if any.
+func (r hedgeResult) discard() {
+	if r.resp != nil && r.resp.Body != nil {
+		r.resp.Body.Close()
+	}
+
+	r.cancel()
+}
+
+// hedge wraps `do`, hedging each call according to `config`. Requests which
+// body can't be got again, see `http.Request.GetBody`, aren't hedged.
+//
+// NOTE: The caller gates the first attempt, handing its slot over. Hedged
+// ones pass through `gate` on their own, so each takes its own rate limit
+// token, and bulkhead slot.
+//
+//nolint:gocognit
+func (c *Client) hedge(do RoundTripFunc, gate gateFunc, config *HedgingConfig) heldRoundTripFunc {
+	// single does an attempt which isn't hedged.
+	single := func(req *http.Request, release func()) (*http.Response, error) {
+		resp, err := do(req)
+
+		return releaseOnClose(resp, err, release), err
+	}
+
+	if config == nil || config.MaxAttempts < 2 || config.Delay <= 0 {
+		return single
+	}
+
+	return func(req *http.Request, release func()) (*http.Response, error) {
+		if !config.AnyMethod && !IsIdempotentMethod(req.Method) {
+			return single(req, release)
+		}
+
+		hasBody := req.Body != nil && req.Body != http.NoBody
+
+		if hasBody && req.GetBody == nil {
+			return single(req, release)
+		}
+
+		results := make(chan hedgeResult, config.MaxAttempts)
+
+		cancels := make([]context.CancelFunc, 0, config.MaxAttempts)
+
+		// launch fires an attempt, with its own context, so it can be canceled
+		// without affecting the others.
+		launch := func(number int) {
+			ctx, cancel := context.WithCancel(req.Context())
+
+			cancels = append(cancels, cancel)
+
+			attemptReq := req.WithContext(ctx)
+
+			if number > 1 {
+				attemptReq = req.Clone(ctx)
+
+				if hasBody {
+					body, err := req.GetBody()
+					if err != nil {
+						results <- hedgeResult{cancel: cancel, err: err, number: number}
+
+						return
+					}
+
+					attemptReq.Body = body
+				}
+			}
+
+			go func() {
+				// Each copy holds its own slot, the first one the caller's.
+				held := release
+
+				if number > 1 {
+					var err error
+
+					held, err = gate(attemptReq)
+					if err != nil {
+						results <- hedgeResult{cancel: cancel, err: err, number: number}
+
+						return
+					}
+
+					c.counterHedged.Add(1)
+				}
+
+				resp, err := do(attemptReq)
+
+				results <- hedgeResult{cancel: cancel, err: err, number: number, resp: releaseOnClose(resp, err, held)}
+			}()
+		}
+
+		launched, pending := 1, 1
+
+		launch(launched)
+
+		timer := time.NewTimer(config.Delay)
+		defer timer.Stop()
+
+		var failure *hedgeResult
+
+		for {
+			select {
+			case result := <-results:
+				pending--
+
+				if result.isSuccess() {
+					// Losers are canceled right away, and drained in the background.
+					for i, cancel := range cancels {
+						if i+1 != result.number {
+							cancel()
+						}
+					}
+
+					go func(pending int) {
+						for ; pending > 0; pending-- {
+							(<-results).discard()
+						}
+					}(pending)
+
+					if failure != nil {
+						failure.discard()
+					}
+
+					return cancelOnClose(result.resp, nil, result.cancel), nil
+				}
+
+				if failure != nil {
+					failure.discard()
+				}
+
+				failure = &result
+
+				if pending > 0 {
+					continue
+				}
+
+				// A failure is still an answer. It's up to the retry policy, and
+				// backoff to try again, hedges only fire on the timer.
+				return cancelOnClose(failure.resp, failure.err, failure.cancel), failure.err
+			case <-timer.C:
+				if launched < config.MaxAttempts {
+					launched++
+					pending++
+
+					launch(launched)
+
+					timer.Reset(config.Delay)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_request_hedging(t *testing.T) {
+	tests := []struct {
+		name          string
+		clientName    string
+		method        string
+		o             []Func
+		slowHits      int32
+		wantErr       bool
+		expectedHits  int32
+		expectedHedge int64
+		expectedMax   time.Duration
+	}{
+		{
+			name:          "should work - hedge wins",
+			clientName:    "hedgewins",
+			method:        http.MethodGet,
+			o:             []Func{WithHedging(50*time.Millisecond, 2), WithReqBody("body")},
+			slowHits:      1,
+			expectedHits:  2,
+			expectedHedge: 1,
+			expectedMax:   400 * time.Millisecond,
+		},
+		{
+			name:          "should work - first answers before the delay",
+			clientName:    "hedgefast",
+			method:        http.MethodGet,
+			o:             []Func{WithHedging(200*time.Millisecond, 3)},
+			expectedHits:  1,
+			expectedHedge: 0,
+			expectedMax:   200 * time.Millisecond,
+		},
+		{
+			name:          "should work - non-idempotent isn't hedged",
+			clientName:    "hedgepost",
+			method:        http.MethodPost,
+			o:             []Func{WithHedging(50*time.Millisecond, 2)},
+			slowHits:      1,
+			expectedHits:  1,
+			expectedHedge: 0,
+			expectedMax:   time.Second,
+		},
+		{
+			name:          "should work - non-idempotent allowed",
+			clientName:    "hedgepostallowed",
+			method:        http.MethodPost,
+			o:             []Func{WithHedging(50*time.Millisecond, 2), WithHedgingAnyMethod()},
+			slowHits:      1,
+			expectedHits:  2,
+			expectedHedge: 1,
+			expectedMax:   400 * time.Millisecond,
+		},
+		{
+			name:          "should fail - a failure isn't hedged right away",
+			clientName:    "hedgefail",
+			method:        http.MethodGet,
+			o:             []Func{WithHedging(time.Second, 3)},
+			slowHits:      -1,
+			wantErr:       true,
+			expectedHits:  1,
+			expectedHedge: 0,
+			expectedMax:   500 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				hits     int32
+				canceled int32
+			)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hit := atomic.AddInt32(&hits, 1)
+
+				// Reads the body, so the client going away is noticed.
+				_, _ = io.Copy(io.Discard, r.Body)
+
+				if tt.slowHits < 0 {
+					w.WriteHeader(http.StatusInternalServerError)
+
+					return
+				}
+
+				if hit <= tt.slowHits {
+					select {
+					case <-time.After(600 * time.Millisecond):
+					case <-r.Context().Done():
+						atomic.AddInt32(&canceled, 1)
+
+						return
+					}
+				}
+
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			c, err := Initialize(WithClientName(tt.clientName))
+			assert.NoError(t, err)
+
+			noRetry := WithRetryPolicy(RetryPolicyFunc(func(attempt *Attempt) bool { return false }))
+
+			now := time.Now()
+
+			resp, err := c.Do(ctx, tt.method, server.URL, append(tt.o, noRetry)...)
+
+			assert.Less(t, time.Since(now), tt.expectedMax)
+
+			if tt.wantErr {
+				assert.True(t, IsServerError(err))
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.NoError(t, resp.Body.Close())
+			}
+
+			assert.Equal(t, tt.expectedHits, atomic.LoadInt32(&hits))
+			assert.Equal(t, tt.expectedHedge, c.counterHedged.Value())
+
+			// The slow loser is canceled.
+			if tt.expectedHedge > 0 && !tt.wantErr {
+				assert.Eventually(t, func() bool {
+					return atomic.LoadInt32(&canceled) == 1
+				}, time.Second, 10*time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestClient_request_hedgingGated(t *testing.T) {
+	tests := []struct {
+		name          string
+		clientName    string
+		o             []ClientFunc
+		expectedHits  int32
+		expectedHedge int64
+	}{
+		{
+			name:       "should work - each hedge takes a bulkhead slot",
+			clientName: "hedgebulkhead",
+			o: []ClientFunc{
+				WithClientBulkhead(BulkheadConfig{MaxInFlight: 1}),
+			},
+		},
+		{
+			name:       "should work - each hedge takes a rate limit token",
+			clientName: "hedgeratelimit",
+			o: []ClientFunc{
+				WithClientBulkhead(BulkheadConfig{MaxInFlight: 1}),
+				WithClientRateLimit(RateLimitConfig{RequestsPerSecond: 1}),
+			},
+			expectedHits:  1,
+			expectedHedge: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits, inFlight, maxInFlight int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&hits, 1)
+
+				n := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+
+				for {
+					max := atomic.LoadInt32(&maxInFlight)
+					if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+						break
+					}
+				}
+
+				select {
+				case <-time.After(200 * time.Millisecond):
+				case <-r.Context().Done():
+				}
+
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			sink := &testMetricsSink{}
+
+			c, err := Initialize(append(tt.o, WithClientName(tt.clientName), WithClientMetricsSink(sink))...)
+			assert.NoError(t, err)
+
+			resp, err := c.Get(ctx, server.URL, WithHedging(20*time.Millisecond, 4))
+			if assert.NoError(t, err) {
+				assert.NoError(t, resp.Body.Close())
+			}
+
+			// Waits for the hedges to be drained.
+			assert.Eventually(t, func() bool {
+				return c.gaugeInFlight.Value() == 0 && c.gaugeQueued.Value() == 0
+			}, time.Second, 10*time.Millisecond)
+
+			assert.Equal(t, int32(1), atomic.LoadInt32(&maxInFlight))
+
+			if tt.expectedHits > 0 {
+				assert.Equal(t, tt.expectedHits, atomic.LoadInt32(&hits))
+				assert.Equal(t, tt.expectedHedge, c.counterHedged.Value())
+			}
+
+			// Each copy sent is measured.
+			sink.mu.Lock()
+			defer sink.mu.Unlock()
+
+			assert.Len(t, sink.observed, int(atomic.LoadInt32(&hits)))
+			assert.Equal(t, int64(0), sink.inFlight)
+		})
+	}
+}
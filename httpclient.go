@@ -1,9 +1,9 @@
 package httpclient
 
 import (
+	"bytes"
 	"context"
 	"errors"
-	"expvar"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,16 +12,16 @@ import (
 	"sync"
 	"time"
 
-	"github.com/eapache/go-resiliency/retrier"
 	"github.com/thalesfsp/customerror"
 	"github.com/thalesfsp/status"
 	"github.com/thalesfsp/sypl"
 	"github.com/thalesfsp/sypl/fields"
 	"github.com/thalesfsp/sypl/level"
 	"github.com/thalesfsp/validation"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/thalesfsp/httpclient/internal/logging"
-	"github.com/thalesfsp/httpclient/internal/metrics"
 	"github.com/thalesfsp/httpclient/internal/shared"
 )
 
@@ -43,19 +43,69 @@ var (
 type Client struct {
 	client *http.Client
 
-	// Request's metrics.
-	counterFailed  *expvar.Int `json:"-" validate:"required,gte=0"`
-	counterRetried *expvar.Int `json:"-" validate:"required,gte=0"`
-	counterSuccess *expvar.Int `json:"-" validate:"required,gte=0"`
+	// Metrics, shared by clients with the same name, and prefix.
+	*clientMetrics
+
+	// rateLimiter throttles requests, if set.
+	rateLimiter *rateLimiter
+
+	// circuitBreakers protect hosts, if set.
+	circuitBreakers *circuitBreakers
+
+	// bulkhead limits in-flight requests, if set.
+	bulkhead *bulkhead
+
+	// metricsSink receives the per-attempt metrics, expvar's first.
+	metricsSink MetricsSink
+
+	// tracer traces requests, and attempts, if set. The trace context is
+	// injected into the outgoing headers by the propagator.
+	propagator propagation.TextMapPropagator
+	tracer     trace.Tracer
 
 	Logger sypl.ISypl `json:"-" validate:"required"`
 
+	// BaseURL relative request URLs are resolved against.
+	BaseURL string `json:"baseURL" validate:"omitempty,url"`
+
 	Headers map[string]string `json:"-" validate:"omitempty,gt=0"`
-	Name    string            `json:"name" validate:"required,lowercase,gte=1"`
-	Timeout time.Duration     `json:"timeout" validate:"omitempty,gte=100ms"`
+
+	// Middlewares wrap every attempt of every request. They run in order, the
+	// first is the outermost, before per-request ones, see `WithMiddleware`.
+	Middlewares []Middleware `json:"-"`
+
+	Name    string        `json:"name" validate:"required,lowercase,gte=1"`
+	Timeout time.Duration `json:"timeout" validate:"omitempty,gte=100ms"`
+
+	// RedirectIsError turns final `3xx` responses, e.g.: not followed
+	// redirects, into errors. It can be overridden per-request, see
+	// `WithRedirectIsError`.
+	RedirectIsError bool `json:"redirectIsError"`
+
+	// Redactor redacts secrets, from headers, URLs, and bodies, before they
+	// are logged.
+	Redactor *Redactor `json:"-"`
 
 	RetrierBackoffDuration time.Duration `json:"retrierBackoffDuration" validate:"omitempty,gte=100ms"`
 	RetrierBackoffTimes    int           `json:"retrierBackoffTimes" validate:"omitempty,gte=1"`
+
+	// Backoff computes the wait between retries. Defaults to
+	// `ExponentialBackoff` starting at `RetrierBackoffDuration`. It can be
+	// overridden per-request, see `WithBackoff`.
+	Backoff Backoff `json:"-"`
+
+	// RetryPolicy decides which failed attempts are retried. Defaults to
+	// `DefaultRetryPolicy`. It can be overridden per-request, see
+	// `WithRetryPolicy`.
+	RetryPolicy RetryPolicy `json:"-"`
+
+	// RetrierMaxWait caps the wait between retries, including the one asked by
+	// the server via `Retry-After`, and rate-limit headers. Zero means no cap.
+	RetrierMaxWait time.Duration `json:"retrierMaxWait" validate:"omitempty,gte=0"`
+
+	// RetrierMaxBudget caps the total wait across all retries of a request.
+	// Zero means no cap.
+	RetrierMaxBudget time.Duration `json:"retrierMaxBudget" validate:"omitempty,gte=0"`
 }
 
 //////
@@ -98,6 +148,9 @@ func (c *Client) SetName(name string) *Client {
 //
 // NOTE: Per-request timeout is achieved by using `context.WithTimeout`.
 //
+// NOTE: The request is re-created per attempt, rewinding the body, if any.
+// Middlewares, client's first, wrap each attempt.
+//
 // NOTE: If `respBody` is provided, it will read and decode the body, ALSO
 // CLOSING IT. Otherwise, the body will be left open, and returned. In this case
 // IT'S THE CALLER'S RESPONSIBILITY TO CLOSE THE BODY.
@@ -109,14 +162,10 @@ func (c *Client) request(
 	url string,
 	o ...Func,
 ) (*http.Response, error) {
-	// Basic validation.
-	if method == "" || url == "" {
-		return nil, customerror.NewRequiredError("method and url are")
-	}
-
 	// Initialize the options.
 	options := &Options{
 		Headers:     make(map[string]string),
+		PathParams:  make(map[string]string),
 		QueryParams: make(map[string]string),
 		ReqBody:     nil,
 		RespBody:    nil,
@@ -131,78 +180,73 @@ func (c *Client) request(
 		}
 	}
 
-	//////
-	// Create request.
-	//////
+	// URL template, before path params are filled, see `RateLimitPerRoute`.
+	route := routeOf(url, options.PathParams)
 
-	req, err := http.NewRequestWithContext(ctx, method, url, options.reqBodyAsIOReader)
+	// Fills path params, and resolves relative URLs against the base URL.
+	url, err := c.resolveURL(url, options.PathParams)
 	if err != nil {
-		return nil, customerror.NewFailedToError("create request", customerror.WithError(err))
+		return nil, err
+	}
+
+	// Basic validation.
+	if method == "" || url == "" {
+		return nil, customerror.NewRequiredError("method and url are")
 	}
 
 	//////
-	// Setup query params.
+	// Create request.
 	//////
 
-	if len(options.QueryParams) > 0 {
-		q := req.URL.Query()
+	// Request body is wrapped, allowing it to be replayed by retries.
+	replayableBody := newReqBody(options.reqBodyAsIOReader)
 
-		for k, v := range options.QueryParams {
-			q.Add(k, v)
-		}
+	// Replayable bodies are hidden from `net/http` closing them, so the client
+	// does, once there are no more attempts.
+	defer replayableBody.Close()
 
-		req.URL.RawQuery = q.Encode()
+	if options.baseReq != nil {
+		replayableBody.getBody = options.baseReq.GetBody
 	}
 
-	//////
-	// Setup headers.
-	//////
-
-	// From default headers.
-	if c.Headers != nil {
-		for k, v := range c.Headers {
-			req.Header.Set(k, v)
-		}
-
-		c.Logger.PrintlnWithOptions(
-			level.Trace,
-			fmt.Sprintf("default headers"),
-			sypl.WithField("headers", c.Headers),
-		)
+	req, err := c.newRequest(ctx, method, url, options, replayableBody)
+	if err != nil {
+		return nil, err
 	}
 
-	// Per-request headers.
-	if options.Headers != nil {
-		for k, v := range options.Headers {
-			req.Header.Set(k, v)
-		}
+	c.Logger.PrintlnWithOptions(
+		level.Trace,
+		"default headers",
+		sypl.WithField("headers", c.Redactor.HeaderMap(c.Headers)),
+	)
 
-		c.Logger.PrintlnWithOptions(
-			level.Trace,
-			fmt.Sprintf("per-request headers"),
-			sypl.WithField("headers", c.Headers),
-		)
-	}
+	c.Logger.PrintlnWithOptions(
+		level.Trace,
+		"per-request headers",
+		sypl.WithField("headers", c.Redactor.HeaderMap(options.Headers)),
+	)
 
 	//////
 	// Setup log fields.
+	//
+	// NOTE: Secrets are redacted before anything reaches the logger.
 	//////
 
 	reqFields := fields.Fields{
 		"method": method,
-		"url":    url,
+		"url":    c.Redactor.URL(url),
 	}
 
 	if options.Headers != nil {
-		reqFields["headers"] = req.Header
+		reqFields["headers"] = c.Redactor.Header(req.Header)
 	}
 
 	if options.ReqBody != nil {
-		reqFields["reqBody"] = options.ReqBody
+		reqFields["reqBody"] = c.Redactor.Body(options.ReqBody)
 	}
 
 	if req.URL.RawQuery != "" {
-		reqFields["queryParams"] = req.URL.RawQuery
+		reqFields["queryParams"] = c.Redactor.Query(req.URL.RawQuery)
 	}
 
 	// Request <-> Transaction <-> Log correlation.
@@ -225,19 +269,100 @@ func (c *Client) request(
 	// Send request.
 	//////
 
-	r := retrier.New(
-		retrier.ExponentialBackoff(c.RetrierBackoffTimes, c.RetrierBackoffDuration),
-		HTTPStatusCodeClassifier{
-			Regex: httpRetrierRegex,
-		},
-	)
+	// Client's middlewares run before per-request ones.
+	middlewares := make([]Middleware, 0, len(c.Middlewares)+len(options.Middlewares))
+	middlewares = append(middlewares, c.Middlewares...)
+	middlewares = append(middlewares, options.Middlewares...)
+
+	// Request span, parent of the attempts ones, see `WithClientTracerProvider`.
+	spanCtx, endSpan := c.startSpan(ctx, req)
+
+	// Elastic APM span, if there's a transaction in the context.
+	spanCtx, endAPMSpan := c.startAPMSpan(spanCtx, req)
+
+	chained := chainMiddlewares(c.client.Do, middlewares...)
+
+	// Each copy of an attempt, hedged ones included, is measured.
+	measured := func(req *http.Request) (*http.Response, error) {
+		finishAttemptMetrics := c.startAttemptMetrics(req, route)
+
+		resp, err := chained(req)
+
+		return finishAttemptMetrics(resp, err), err
+	}
 
-	var resp *http.Response
+	// gate waits for the rate limiter, and a bulkhead slot.
+	gate := func(req *http.Request) (func(), error) {
+		if err := c.waitRateLimit(req.Context(), method, route, req); err != nil {
+			return nil, err
+		}
+
+		return c.acquireBulkhead(req.Context(), req)
+	}
 
-	if err := r.Run(func() error {
-		req.Close = true
+	// Hedges are attempts, so they go through the middlewares, and gate.
+	do := c.hedge(measured, gate, options.Hedging)
+
+	attempt := 0
+
+	// send does a single attempt.
+	send := func() (*http.Response, error) {
+		attempt++
+
+		// Each retry needs a fresh request, otherwise the body, already
+		// consumed by the previous attempt, would be sent empty.
+		if attempt > 1 {
+			var err error
+
+			req, err = c.newRequest(ctx, method, url, options, replayableBody)
+			if err != nil {
+				c.counterFailed.Add(1)
+
+				c.GetLogger().PrintlnWithOptions(
+					level.Error,
+					err.Error(),
+					sypl.WithFields(respFields),
+					sypl.WithTags("request"),
+				)
+
+				return nil, err
+			}
+		}
+
+		release, err := gate(req)
+		if err != nil {
+			c.counterFailed.Add(1)
+
+			c.GetLogger().PrintlnWithOptions(
+				level.Error,
+				err.Error(),
+				sypl.WithFields(respFields),
+				sypl.WithTags("request"),
+			)
+
+			return nil, err
+		}
+
+		// Attempt spans are children of the request ones.
+		req, endAttemptSpan := c.startAttemptSpan(req.WithContext(spanCtx), attempt)
+		req, endAPMAttemptSpan := c.startAPMAttemptSpan(req, attempt)
+
+		handed := false
+
+		resp, err := c.runCircuitBreaker(ctx, req, func() (*http.Response, error) {
+			handed = true
+
+			return do(req, release)
+		})
+
+		// Rejected by the circuit breaker, the slot wasn't handed over.
+		if !handed {
+			release()
+		}
+
+		endAPMAttemptSpan(resp, err)
+		endAttemptSpan(resp, err)
 
-		resp, err = c.client.Do(req)
 		if err != nil {
 			c.counterFailed.Add(1)
 
@@ -256,12 +381,12 @@ func (c *Client) request(
 					body, err := shared.ReadAll(resp.Body)
 					if err != nil {
 						c.GetLogger().Errorln(customerror.NewFailedToError(
-							fmt.Sprintf("read response body %s", url),
+							fmt.Sprintf("read response body %s", c.Redactor.URL(url)),
 							customerror.WithError(err),
 						))
 					}
 
-					respFields["respBody"] = string(body)
+					respFields["respBody"] = c.Redactor.Body(body)
 				}
 			}
 
@@ -272,87 +397,95 @@ func (c *Client) request(
 
 			c.GetLogger().PrintlnWithOptions(
 				level.Error,
-				cE.Error(),
+				c.Redactor.Message(cE.Error(), url, req.URL.String()),
 				sypl.WithFields(respFields),
 				sypl.WithTags("request"),
 			)
 
-			return cE
+			return resp, cE
 		}
 
 		//////
 		// Handles HTTP status codes, and retries.
 		//////
 
+		// If 2xx neither 4xx, return an error with the status code, unless
+		// configured otherwise, see `isStatusError`. Whether it's retried is up
+		// to the retry policy, by default:
+		//
 		// 429 - Retry after at least 1 second; avoid bursts of requests
 		// 4xx - Do not retry
 		// 5xx - Retry 3 times with 5, 10, 15 second pause between retries.
-		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
-			c.counterRetried.Add(1)
+		if c.isStatusError(resp.StatusCode, options) {
+			if resp.Body == nil {
+				return resp, newHTTPError(method, url, resp, nil, customerror.NewHTTPError(resp.StatusCode))
+			}
 
-			var cE error
+			defer resp.Body.Close()
 
-			if resp.Body != nil {
-				var body []byte
+			body, err := shared.ReadAll(resp.Body)
+			if err != nil {
+				return resp, err
+			}
 
-				body, err := shared.ReadAll(resp.Body)
-				if err != nil {
-					return err
-				}
+			if options.ErrBody != nil && len(body) > 0 {
+				//nolint:errcheck
+				shared.Decode(bytes.NewReader(body), options.ErrBody)
+			}
 
-				cE = customerror.NewFailedToError(
-					fmt.Sprintf("request (%s). It may be %s, depending on the error and status code) %s",
-						http.StatusText(resp.StatusCode),
-						status.Retried,
-						url,
-					),
+			if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < http.StatusInternalServerError {
+				return resp, newHTTPError(method, url, resp, body, customerror.NewFailedToError(
+					fmt.Sprintf("request errored %s", url),
 					customerror.WithStatusCode(resp.StatusCode),
 					customerror.WithError(errors.New(string(body))),
-				)
-			} else {
-				cE = customerror.NewFailedToError(
-					fmt.Sprintf("request (%s). It may be %s, depending on the error and status code) %s",
-						http.StatusText(resp.StatusCode),
-						status.Retried,
-						url,
-					),
-					customerror.WithStatusCode(resp.StatusCode),
-				)
+				))
+			}
+
+			msg := fmt.Sprintf("request (%s). It may be %s, depending on the error and status code) %s",
+				http.StatusText(resp.StatusCode),
+				status.Retried,
+				url,
+			)
+
+			cE := customerror.NewFailedToError(
+				msg,
+				customerror.WithStatusCode(resp.StatusCode),
+				customerror.WithError(errors.New(string(body))),
+			)
+
+			// The body is logged redacted, as a field, instead of as part of
+			// the error message.
+			logFields := make(fields.Fields, len(respFields)+1)
+
+			for k, v := range respFields {
+				logFields[k] = v
 			}
 
+			logFields["respBody"] = c.Redactor.Body(body)
+
 			c.GetLogger().PrintlnWithOptions(
 				level.Error,
-				cE.Error(),
-				sypl.WithFields(respFields),
+				customerror.NewFailedToError(
+					c.Redactor.Message(msg, url),
+					customerror.WithStatusCode(resp.StatusCode),
+				).Error(),
+				sypl.WithFields(logFields),
 				sypl.WithTags("request"),
 			)
 
-			return cE
+			return resp, newHTTPError(method, url, resp, body, cE)
 		}
 
-		return nil
-	}); err != nil {
-		return nil, err
+		return resp, nil
 	}
 
-	// If 2xx neither 4xx, return an error with the status code.
-	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
-		if resp.Body != nil {
-			var body []byte
-
-			body, err := shared.ReadAll(resp.Body)
-			if err != nil {
-				return nil, err
-			}
+	resp, err := c.retry(ctx, method, url, c.newRetryConfig(options), send)
 
-			return nil, customerror.NewFailedToError(
-				fmt.Sprintf("request errored %s", url),
-				customerror.WithStatusCode(resp.StatusCode),
-				customerror.WithError(errors.New(string(body))),
-			)
-		}
+	endAPMSpan(resp, err)
+	endSpan(resp, err)
 
-		return nil, customerror.NewHTTPError(resp.StatusCode)
+	if err != nil {
+		return nil, err
 	}
 
 	c.counterSuccess.Add(1)
@@ -361,8 +494,32 @@ func (c *Client) request(
 	// Handles response body.
 	//////
 
-	//nolint:gocritic
-	if options.RespBody != nil {
+	// Expected non-2xx responses are returned with the body intact, see
+	// `WithExpectedStatus`.
+	if !IsRespSuccess(resp) {
+		if options.ErrBody != nil && resp.Body != nil {
+			body, err := shared.ReadAll(resp.Body)
+
+			resp.Body.Close()
+
+			if err != nil {
+				return resp, err
+			}
+
+			resp.Body = releasedBody{io.NopCloser(bytes.NewReader(body))}
+
+			if len(body) > 0 {
+				if err := shared.Decode(bytes.NewReader(body), options.ErrBody); err != nil {
+					return resp, customerror.NewFailedToError(
+						fmt.Sprintf("decode error body %s", url),
+						customerror.WithError(err),
+						customerror.WithField("respBody", string(body)),
+					)
+				}
+			}
+		}
+	} else if options.RespBody != nil {
+		//nolint:gocritic
 		switch options.RespBody.(type) {
 		case *os.File:
 			// Write the response body to file
@@ -371,11 +528,27 @@ func (c *Client) request(
 			}
 
 		default:
-			if err := shared.Decode(resp.Body, options.RespBody); err != nil {
+			body, err := shared.ReadAll(resp.Body)
+
+			resp.Body.Close()
+
+			resp.Body = releasedBody{resp.Body}
+
+			if err != nil {
 				return resp, err
 			}
 
-			respFields["respBody"] = fmt.Sprintf("%+v", options.RespBody)
+			if len(body) > 0 {
+				if err := shared.Decode(bytes.NewReader(body), options.RespBody); err != nil {
+					return resp, customerror.NewFailedToError(
+						fmt.Sprintf("decode response body %s", url),
+						customerror.WithError(err),
+						customerror.WithField("respBody", string(body)),
+					)
+				}
+			}
+
+			respFields["respBody"] = c.Redactor.Body(options.RespBody)
 		}
 	}
 
@@ -391,6 +564,69 @@ func (c *Client) request(
 	return resp, nil
 }
 
+// newRequest creates the request, setting query params, and headers. It's
+// called once per attempt.
+func (c *Client) newRequest(
+	ctx context.Context,
+	method string,
+	url string,
+	options *Options,
+	body *reqBody,
+) (*http.Request, error) {
+	reqBodyReader, err := body.Reader()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBodyReader)
+	if err != nil {
+		return nil, customerror.NewFailedToError("create request", customerror.WithError(err))
+	}
+
+	//////
+	// Setup query params.
+	//////
+
+	if len(options.QueryParams) > 0 {
+		q := req.URL.Query()
+
+		for k, v := range options.QueryParams {
+			q.Add(k, v)
+		}
+
+		req.URL.RawQuery = q.Encode()
+	}
+
+	//////
+	// Setup headers.
+	//////
+
+	// From default headers.
+	for k, v := range c.Headers {
+		req.Header.Set(k, v)
+	}
+
+	// From the caller-built request, see `DoRequest`.
+	if options.baseReq != nil {
+		for k, v := range options.baseReq.Header {
+			req.Header[k] = append([]string(nil), v...)
+		}
+
+		req.Host = options.baseReq.Host
+
+		if options.baseReq.ContentLength > 0 {
+			req.ContentLength = options.baseReq.ContentLength
+		}
+	}
+
+	// Per-request headers.
+	for k, v := range options.Headers {
+		req.Header.Set(k, v)
+	}
+
+	return req, nil
+}
+
 //////
 // Exported functionalities.
 //////
@@ -417,13 +653,15 @@ func IsRespSuccess(resp *http.Response) bool {
 // - Timeout: 30s
 // - Retrier Times: 3x
 // - Retrier Initial Time: 1s
+// - Retrier Max Wait: 30s
 //
 // NOTE: `headers` sets default headers for all requests.
 //
 // NOTE: Retrier use exponential backoff (e.g.: 1s -> 2s -> 4s). Be mindful:
-// `timeout` can't be less than the cumulative retrier time.
+// `timeout` can't be less than the cumulative retrier time. Set `Backoff` to
+// use another strategy, e.g.: `ExponentialFullJitterBackoff`.
 //
-//nolint:lll
+// NOTE: For further customization, see `Initialize`.
 func New(
 	name string,
 	headers map[string]string,
@@ -431,38 +669,122 @@ func New(
 	retrierBackoffDuration time.Duration,
 	retrierBackoffTimes int,
 ) (*Client, error) {
+	return newClient(&ClientOptions{
+		Headers:                headers,
+		Name:                   name,
+		RetrierBackoffDuration: retrierBackoffDuration,
+		RetrierBackoffTimes:    retrierBackoffTimes,
+		Timeout:                timeout,
+	})
+}
+
+// newClient creates a new HTTP client based on the options. Zero values fall
+// back to the defaults, see `New`.
+//
+//nolint:lll
+func newClient(o *ClientOptions) (*Client, error) {
 	// Enforces IHTTP interface implementation.
 	var _ IHTTP = (*Client)(nil)
 
-	logger := logging.Get().New(name).SetTags(shared.PackageName, name)
+	name := o.Name
+
+	logger := o.Logger
+	if logger == nil {
+		logger = logging.Get().New(name).SetTags(shared.PackageName, name)
+	}
+
+	transport, err := newClientTransport(o)
+	if err != nil {
+		return nil, err
+	}
+
+	latencyBuckets := DefaultLatencyBuckets
+
+	if len(o.LatencyBuckets) > 0 {
+		latencyBuckets = o.LatencyBuckets
+	}
 
 	client := &Client{
 		client: &http.Client{
-			Timeout: timeout,
+			Timeout:   o.Timeout,
+			Transport: transport,
 		},
 
-		//////
-		// Request's metrics.
-		//////
-
-		counterFailed:  metrics.NewInt(fmt.Sprintf("%s.%s.%s.%s", shared.PackageName, name, status.Failed, DefaultMetricCounterLabel)),
-		counterRetried: metrics.NewInt(fmt.Sprintf("%s.%s.%s.%s", shared.PackageName, name, status.Retried, DefaultMetricCounterLabel)),
-		counterSuccess: metrics.NewInt(fmt.Sprintf("%s.%s.%s.%s", shared.PackageName, name, status.Succeeded, DefaultMetricCounterLabel)),
+		clientMetrics: registerMetrics(o.MetricsPrefix, name, latencyBuckets),
 
 		Logger: logger,
 
-		Headers:                headers,
+		Backoff:                o.Backoff,
+		BaseURL:                o.BaseURL,
+		Headers:                o.Headers,
+		Middlewares:            o.Middlewares,
 		Name:                   name,
+		Redactor:               o.Redactor,
+		RedirectIsError:        o.RedirectIsError,
 		RetrierBackoffDuration: 1 * time.Second,
 		RetrierBackoffTimes:    3,
+		RetrierMaxBudget:       o.RetrierMaxBudget,
+		RetrierMaxWait:         shared.Timeout,
+		RetryPolicy:            DefaultRetryPolicy,
+		Timeout:                o.Timeout,
 	}
 
-	if retrierBackoffDuration > 0 {
-		client.RetrierBackoffDuration = retrierBackoffDuration
+	if o.RateLimit != nil {
+		rateLimiter, err := newRateLimiter(*o.RateLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		client.rateLimiter = rateLimiter
 	}
 
-	if retrierBackoffTimes > 0 {
-		client.RetrierBackoffTimes = retrierBackoffTimes
+	if o.Bulkhead != nil {
+		bulkhead, err := newBulkhead(*o.Bulkhead)
+		if err != nil {
+			return nil, err
+		}
+
+		client.bulkhead = bulkhead
+	}
+
+	if o.CircuitBreaker != nil {
+		circuitBreakers, err := newCircuitBreakers(*o.CircuitBreaker)
+		if err != nil {
+			return nil, err
+		}
+
+		client.circuitBreakers = circuitBreakers
+	}
+
+	if client.Redactor == nil {
+		client.Redactor = NewRedactor(nil, nil, nil)
+	}
+
+	client.metricsSink = append(multiSink{client.expvar, client.prometheus}, o.MetricsSinks...)
+
+	if o.TracerProvider != nil {
+		client.tracer = o.TracerProvider.Tracer(TracerName)
+		client.propagator = o.Propagator
+
+		if client.propagator == nil {
+			client.propagator = propagation.TraceContext{}
+		}
+	}
+
+	if o.RetrierBackoffDuration > 0 {
+		client.RetrierBackoffDuration = o.RetrierBackoffDuration
+	}
+
+	if o.RetrierBackoffTimes > 0 {
+		client.RetrierBackoffTimes = o.RetrierBackoffTimes
+	}
+
+	if o.RetrierMaxWait > 0 {
+		client.RetrierMaxWait = o.RetrierMaxWait
+	}
+
+	if o.RetryPolicy != nil {
+		client.RetryPolicy = o.RetryPolicy
 	}
 
 	// Validate the HTTP client.
@@ -538,14 +860,12 @@ func NewDefaultSingleton(name string) (*Client, error) {
 // Content-Type: "application/json"
 // User-Agent:   "name"
 func NewDefault(name string) (*Client, error) {
-	return New(name, map[string]string{
-		"Accept":       "*/*",
-		"Content-Type": "application/json",
-		"User-Agent":   name,
-	}, 0, 0, 0)
+	return New(name, defaultHeaders(name), 0, 0, 0)
 }
 
-// Initialize setup the HTTP client. Use options to customize it.
+// Initialize setup the HTTP client. Use options to customize it. Default
+// headers are the same as `NewDefault`, headers set via options are merged
+// over them.
 func Initialize(opts ...ClientFunc) (*Client, error) {
 	//////
 	// Initialize and process options.
@@ -563,5 +883,22 @@ func Initialize(opts ...ClientFunc) (*Client, error) {
 		options.Name = shared.PackageName + "-" + shared.GenerateUUID()
 	}
 
-	return NewDefault(options.Name)
+	headers := defaultHeaders(options.Name)
+
+	for k, v := range options.Headers {
+		headers[k] = v
+	}
+
+	options.Headers = headers
+
+	return newClient(options)
+}
+
+// defaultHeaders returns the default headers, see `NewDefault`.
+func defaultHeaders(name string) map[string]string {
+	return map[string]string{
+		"Accept":       "*/*",
+		"Content-Type": "application/json",
+		"User-Agent":   name,
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -58,3 +59,40 @@ func TestInitialize(t *testing.T) {
 	assert.Contains(t, name, "httpclient")
 	assert.Len(t, name, 47)
 }
+
+func TestInitialize_withClientOptions(t *testing.T) {
+	transport := &http.Transport{}
+
+	got, err := Initialize(
+		WithClientName("testclientoptions"),
+		WithClientHeader("X-Test", "test"),
+		WithClientHeaders(map[string]string{"Accept": "application/json"}),
+		WithClientTimeout(5*time.Second),
+		WithClientRetrier(200*time.Millisecond, 5),
+		WithClientRetrierMaxWait(10*time.Second),
+		WithClientRetrierMaxBudget(20*time.Second),
+		WithClientBackoff(ConstantBackoff(time.Second)),
+		WithClientRetryPolicy(NeverRetryPolicy),
+		WithClientTransport(transport),
+	)
+	if err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	assert.Equal(t, "testclientoptions", got.GetName())
+	assert.Equal(t, map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+		"User-Agent":   "testclientoptions",
+		"X-Test":       "test",
+	}, got.Headers)
+	assert.Equal(t, 5*time.Second, got.Timeout)
+	assert.Equal(t, 5*time.Second, got.GetClient().Timeout)
+	assert.Equal(t, 200*time.Millisecond, got.RetrierBackoffDuration)
+	assert.Equal(t, 5, got.RetrierBackoffTimes)
+	assert.Equal(t, 10*time.Second, got.RetrierMaxWait)
+	assert.Equal(t, 20*time.Second, got.RetrierMaxBudget)
+	assert.NotNil(t, got.Backoff)
+	assert.NotNil(t, got.RetryPolicy)
+	assert.Equal(t, transport, got.GetClient().Transport)
+}
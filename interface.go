@@ -7,7 +7,11 @@ import (
 
 // RequestResult is the result of a request.
 type RequestResult struct {
-	Error    error
+	Error error
+
+	// Index is the input index of the request, see `ParallelDoStream`.
+	Index int
+
 	Response *http.Response
 }
 
@@ -46,4 +50,37 @@ type IHTTP interface {
 	// ALSO CLOSING IT. Otherwise, the body will be left open, and returned. In
 	// this case IT'S THE CALLER'S RESPONSIBILITY TO CLOSE THE BODY.
 	Delete(ctx context.Context, url string, o ...Func) (*http.Response, error)
+
+	// Patch does a `PATCH` request.
+	//
+	// NOTE: If `opt.RespBody` is provided, it will read and decode the body,
+	// ALSO CLOSING IT. Otherwise, the body will be left open, and returned. In
+	// this case IT'S THE CALLER'S RESPONSIBILITY TO CLOSE THE BODY.
+	Patch(ctx context.Context, url string, o ...Func) (*http.Response, error)
+
+	// Head does a `HEAD` request.
+	//
+	// NOTE: The response has no body, but it still must be closed.
+	Head(ctx context.Context, url string, o ...Func) (*http.Response, error)
+
+	// Options does an `OPTIONS` request.
+	//
+	// NOTE: If `opt.RespBody` is provided, it will read and decode the body,
+	// ALSO CLOSING IT. Otherwise, the body will be left open, and returned. In
+	// this case IT'S THE CALLER'S RESPONSIBILITY TO CLOSE THE BODY.
+	Options(ctx context.Context, url string, o ...Func) (*http.Response, error)
+
+	// Do does a request with any `method`.
+	//
+	// NOTE: If `opt.RespBody` is provided, it will read and decode the body,
+	// ALSO CLOSING IT. Otherwise, the body will be left open, and returned. In
+	// this case IT'S THE CALLER'S RESPONSIBILITY TO CLOSE THE BODY.
+	Do(ctx context.Context, method, url string, o ...Func) (*http.Response, error)
+
+	// DoRequest does a caller-built request, through the same pipeline.
+	//
+	// NOTE: If `opt.RespBody` is provided, it will read and decode the body,
+	// ALSO CLOSING IT. Otherwise, the body will be left open, and returned. In
+	// this case IT'S THE CALLER'S RESPONSIBILITY TO CLOSE THE BODY.
+	DoRequest(req *http.Request, o ...Func) (*http.Response, error)
 }
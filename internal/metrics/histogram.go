@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Histogram counts observations into buckets. It implements `expvar.Var`,
+// exposing cumulative buckets, count, and sum, e.g.:
+// `{"buckets":{"0.1":1,"+Inf":2},"count":2,"sum":0.35}`.
+type Histogram struct {
+	mu sync.Mutex
+
+	// bounds are the upper bounds, sorted, of the buckets.
+	bounds []float64
+
+	// counts per bucket, non-cumulative. The last one is `+Inf`.
+	counts []uint64
+
+	count uint64
+	sum   float64
+}
+
+// Observe adds `v` to the histogram.
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.bounds, v)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.counts[i]++
+	h.count++
+	h.sum += v
+}
+
+// Snapshot returns the upper bounds, the cumulative counts per bucket, `+Inf`
+// excluded, the count, and the sum.
+func (h *Histogram) Snapshot() ([]float64, []uint64, uint64, float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cumulative := make([]uint64, len(h.bounds))
+
+	var total uint64
+
+	for i := range h.bounds {
+		total += h.counts[i]
+
+		cumulative[i] = total
+	}
+
+	return h.bounds, cumulative, h.count, h.sum
+}
+
+// String implements the `expvar.Var` interface.
+func (h *Histogram) String() string {
+	bounds, cumulative, count, sum := h.Snapshot()
+
+	buckets := make(map[string]uint64, len(bounds)+1)
+
+	for i, bound := range bounds {
+		buckets[strconv.FormatFloat(bound, 'g', -1, 64)] = cumulative[i]
+	}
+
+	buckets["+Inf"] = count
+
+	b, err := json.Marshal(struct {
+		Buckets map[string]uint64 `json:"buckets"`
+		Count   uint64            `json:"count"`
+		Sum     float64           `json:"sum"`
+	}{buckets, count, sum})
+	if err != nil {
+		return "{}"
+	}
+
+	return string(b)
+}
+
+// NewHistogram creates a histogram with `bounds` as the buckets upper bounds.
+func NewHistogram(bounds []float64) *Histogram {
+	sorted := append([]float64(nil), bounds...)
+
+	sort.Float64s(sorted)
+
+	return &Histogram{
+		bounds: sorted,
+		counts: make([]uint64, len(sorted)+1),
+	}
+}
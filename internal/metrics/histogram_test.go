@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"testing"
+)
+
+func TestHistogram(t *testing.T) {
+	tests := []struct {
+		name         string
+		bounds       []float64
+		observations []float64
+		want         string
+	}{
+		{
+			name:   "should work - empty",
+			bounds: []float64{0.1, 1},
+			want:   `{"buckets":{"+Inf":0,"0.1":0,"1":0},"count":0,"sum":0}`,
+		},
+		{
+			name:         "should work - cumulative, upper bound inclusive",
+			bounds:       []float64{1, 0.1},
+			observations: []float64{0.05, 0.1, 0.5, 2},
+			want:         `{"buckets":{"+Inf":4,"0.1":2,"1":3},"count":4,"sum":2.65}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHistogram(tt.bounds)
+
+			for _, v := range tt.observations {
+				h.Observe(v)
+			}
+
+			if got := h.String(); got != tt.want {
+				t.Errorf("Histogram.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
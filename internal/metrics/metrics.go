@@ -2,26 +2,52 @@ package metrics
 
 import (
 	"expvar"
-	"os"
-	"time"
+	"sync"
 )
 
-// NewInt creates and initializes a new expvar.Int. Name should be in the format of
-// "{packageName}.{subject}.{type}", e.g. "{companyname}.api.rest.failed.counter".
+// mu guards the registration of metrics.
+var mu sync.Mutex
+
+// NewInt returns the expvar.Int published as `name`, creating, and publishing
+// it if needed. Name should be in the format of
+// "{prefix}.{packageName}.{subject}.{type}", e.g.
+// "companyname.httpclient.api.failed.counter".
 //
-// NOTE- All metrics are prefixed with the company name (e.g. companyname).
+// NOTE: Registration is idempotent, the same name returns the same metric.
+// If the name is taken by another kind of metric, the returned one isn't
+// published.
 func NewInt(name string) *expvar.Int {
-	prefix := os.Getenv("HTTPCLIENT_METRICS_PREFIX")
-
-	finalName := name + "--" + time.Now().Format(time.RFC3339)
+	mu.Lock()
+	defer mu.Unlock()
 
-	if prefix != "" {
-		finalName = prefix + "." + finalName
+	switch v := expvar.Get(name).(type) {
+	case *expvar.Int:
+		return v
+	case nil:
+		return expvar.NewInt(name)
+	default:
+		return new(expvar.Int)
 	}
+}
 
-	counter := expvar.NewInt(finalName)
-
-	counter.Set(0)
+// NewMap returns the expvar.Map published as `name`, creating, and publishing
+// it if needed. Name should be in the format of
+// "{prefix}.{packageName}.{subject}.{type}", e.g.
+// "companyname.httpclient.api.circuitbreaker.state".
+//
+// NOTE: Registration is idempotent, the same name returns the same metric.
+// If the name is taken by another kind of metric, the returned one isn't
+// published.
+func NewMap(name string) *expvar.Map {
+	mu.Lock()
+	defer mu.Unlock()
 
-	return counter
+	switch v := expvar.Get(name).(type) {
+	case *expvar.Map:
+		return v
+	case nil:
+		return expvar.NewMap(name)
+	default:
+		return new(expvar.Map).Init()
+	}
 }
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"expvar"
+	"testing"
+)
+
+func TestNewInt(t *testing.T) {
+	counter := NewInt("metrics.test.newint.counter")
+
+	counter.Add(1)
+
+	if got := NewInt("metrics.test.newint.counter"); got != counter {
+		t.Errorf("NewInt() = %v, want the registered one", got)
+	}
+
+	// Taken by another kind of metric.
+	m := NewMap("metrics.test.newint.map")
+
+	if got := NewInt("metrics.test.newint.map"); got == nil || expvar.Get("metrics.test.newint.map") != m {
+		t.Errorf("NewInt() = %v, want an unpublished one", got)
+	}
+}
+
+func TestNewMap(t *testing.T) {
+	m := NewMap("metrics.test.newmap.map")
+
+	m.Add("key", 1)
+
+	if got := NewMap("metrics.test.newmap.map"); got != m {
+		t.Errorf("NewMap() = %v, want the registered one", got)
+	}
+
+	// Taken by another kind of metric.
+	NewInt("metrics.test.newmap.counter")
+
+	if got := NewMap("metrics.test.newmap.counter"); got == nil {
+		t.Errorf("NewMap() = %v, want an unpublished one", got)
+	}
+}
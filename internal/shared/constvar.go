@@ -69,6 +69,15 @@ const (
 
 	// MethodDelete is the HTTP DELETE method.
 	MethodDelete HTTPMethod = http.MethodDelete
+
+	// MethodHead is the HTTP HEAD method.
+	MethodHead HTTPMethod = http.MethodHead
+
+	// MethodOptions is the HTTP OPTIONS method.
+	MethodOptions HTTPMethod = http.MethodOptions
+
+	// MethodTrace is the HTTP TRACE method.
+	MethodTrace HTTPMethod = http.MethodTrace
 )
 
 func (m HTTPMethod) String() string {
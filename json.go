@@ -0,0 +1,76 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// requestJSON does a request, decoding the response body into `T`.
+func requestJSON[T any](
+	ctx context.Context,
+	c *Client,
+	method string,
+	url string,
+	o ...Func,
+) (T, *http.Response, error) {
+	var v T
+
+	// Typed decoding takes precedence over any `WithRespBody`.
+	opts := make([]Func, 0, len(o)+1)
+	opts = append(opts, o...)
+	opts = append(opts, WithRespBody(&v))
+
+	resp, err := c.request(ctx, method, url, opts...)
+
+	// Expected non-2xx responses are returned with the body open, see
+	// `WithExpectedStatus`. It's drained, and closed, so it doesn't leak.
+	if resp != nil && resp.Body != nil {
+		//nolint:errcheck
+		io.Copy(io.Discard, resp.Body)
+
+		resp.Body.Close()
+	}
+
+	return v, resp, err
+}
+
+// GetJSON does a `GET` request, decoding the response body into `T`.
+//
+// NOTE: The body is read, and closed, non-2xx ones included. To decode them,
+// see `WithErrBody`.
+func GetJSON[T any](ctx context.Context, c *Client, url string, o ...Func) (T, *http.Response, error) {
+	return requestJSON[T](ctx, c, http.MethodGet, url, o...)
+}
+
+// PostJSON does a `POST` request, decoding the response body into `T`.
+//
+// NOTE: The body is read, and closed, non-2xx ones included. To decode them,
+// see `WithErrBody`.
+func PostJSON[T any](ctx context.Context, c *Client, url string, o ...Func) (T, *http.Response, error) {
+	return requestJSON[T](ctx, c, http.MethodPost, url, o...)
+}
+
+// PutJSON does a `PUT` request, decoding the response body into `T`.
+//
+// NOTE: The body is read, and closed, non-2xx ones included. To decode them,
+// see `WithErrBody`.
+func PutJSON[T any](ctx context.Context, c *Client, url string, o ...Func) (T, *http.Response, error) {
+	return requestJSON[T](ctx, c, http.MethodPut, url, o...)
+}
+
+// PatchJSON does a `PATCH` request, decoding the response body into `T`.
+//
+// NOTE: The body is read, and closed, non-2xx ones included. To decode them,
+// see `WithErrBody`.
+func PatchJSON[T any](ctx context.Context, c *Client, url string, o ...Func) (T, *http.Response, error) {
+	return requestJSON[T](ctx, c, http.MethodPatch, url, o...)
+}
+
+// DeleteJSON does a `DELETE` request, decoding the response body into `T`.
+//
+// NOTE: The body is read, and closed, non-2xx ones included. To decode them,
+// see `WithErrBody`.
+func DeleteJSON[T any](ctx context.Context, c *Client, url string, o ...Func) (T, *http.Response, error) {
+	return requestJSON[T](ctx, c, http.MethodDelete, url, o...)
+}
@@ -0,0 +1,154 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/customerror"
+)
+
+type testJSONItem struct {
+	ID     string `json:"id"`
+	Method string `json:"method"`
+}
+
+type testJSONError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func TestRequestJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/item":
+			if _, err := w.Write([]byte(`{"id":"1","method":"` + r.Method + `"}`)); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		case "/invalid":
+			if _, err := w.Write([]byte(`not json`)); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+
+			if _, err := w.Write([]byte(`{"code":"not_found","message":"item not found"}`)); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		}
+	}))
+	defer server.Close()
+
+	c, err := Initialize(
+		WithClientName("json"),
+		WithClientBaseURL(server.URL),
+		WithClientRetryPolicy(NeverRetryPolicy),
+	)
+	assert.NoError(t, err)
+
+	type requestFunc func(ctx context.Context, c *Client, url string, o ...Func) (testJSONItem, *http.Response, error)
+
+	tests := []struct {
+		name   string
+		do     requestFunc
+		method string
+	}{
+		{name: "GetJSON", do: GetJSON[testJSONItem], method: http.MethodGet},
+		{name: "PostJSON", do: PostJSON[testJSONItem], method: http.MethodPost},
+		{name: "PutJSON", do: PutJSON[testJSONItem], method: http.MethodPut},
+		{name: "PatchJSON", do: PatchJSON[testJSONItem], method: http.MethodPatch},
+		{name: "DeleteJSON", do: DeleteJSON[testJSONItem], method: http.MethodDelete},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			item, resp, err := tt.do(ctx, c, "/item")
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, testJSONItem{ID: "1", Method: tt.method}, item)
+		})
+	}
+
+	t.Run("empty body", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		item, resp, err := DeleteJSON[*testJSONItem](ctx, c, "/empty")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Nil(t, item)
+	})
+
+	t.Run("invalid body keeps the raw payload", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_, _, err := GetJSON[testJSONItem](ctx, c, "/invalid")
+		assert.Error(t, err)
+
+		var cE *customerror.CustomError
+
+		assert.True(t, errors.As(err, &cE))
+
+		respBody, ok := cE.Fields.Load("respBody")
+		assert.True(t, ok)
+		assert.Equal(t, "not json", respBody)
+	})
+
+	t.Run("error body", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var apiErr testJSONError
+
+		_, _, err := GetJSON[testJSONItem](ctx, c, "/missing", WithErrBody(&apiErr))
+		assert.Error(t, err)
+		assert.Equal(t, testJSONError{Code: "not_found", Message: "item not found"}, apiErr)
+	})
+
+	t.Run("expected error body is closed", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_, resp, err := GetJSON[testJSONItem](ctx, c, "/missing", WithExpectedStatus(http.StatusNotFound))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		_, err = io.ReadAll(resp.Body)
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_Get_respBodyClosed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := w.Write([]byte(`{"id":"1"}`)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Initialize(WithClientName("jsonrespbodyclosed"))
+	assert.NoError(t, err)
+
+	var item testJSONItem
+
+	resp, err := c.Get(ctx, server.URL, WithRespBody(&item))
+	assert.NoError(t, err)
+	assert.Equal(t, "1", item.ID)
+
+	// Body is consumed, and closed.
+	_, err = io.ReadAll(resp.Body)
+	assert.Error(t, err)
+}
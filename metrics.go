@@ -0,0 +1,303 @@
+package httpclient
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/httpclient/internal/metrics"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// StatusClassError is the status class of attempts which failed without a
+// response, e.g.: connection refused.
+const StatusClassError = "error"
+
+// DefaultLatencyBuckets are the default upper bounds of the request duration
+// histogram buckets.
+var DefaultLatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// MetricLabels identifies what a metric is about.
+type MetricLabels struct {
+	// Client is the client name.
+	Client string
+
+	// Method of the request, e.g.: `GET`.
+	Method string
+
+	// Host of the request, e.g.: `example.com:8080`.
+	Host string
+
+	// Route is the path of the URL template, before path params are filled,
+	// e.g.: `/users/{id}`. Empty if no path param is filled, see
+	// `WithPathParam`, keeping the number of series bounded.
+	Route string
+
+	// StatusClass of the response, e.g.: `2xx`, or `StatusClassError`. Empty
+	// for in-flight, and request bytes metrics.
+	StatusClass string
+}
+
+// MetricsSink receives the client's request metrics, e.g.: to export them to
+// Prometheus, or StatsD. Methods are called concurrently, once per attempt.
+//
+// NOTE: The client metrics are always exposed through `expvar`. Sinks are in
+// addition to it, see `WithClientMetricsSink`.
+type MetricsSink interface {
+	// AddInFlight is called with `1` when an attempt is sent, and `-1` when
+	// its response headers are received, or it fails.
+	AddInFlight(labels MetricLabels, delta int64)
+
+	// AddRequestBytes is called with the request body size, if known.
+	AddRequestBytes(labels MetricLabels, n int64)
+
+	// AddResponseBytes is called with the number of response body bytes read,
+	// when the body is closed.
+	AddResponseBytes(labels MetricLabels, n int64)
+
+	// ObserveRequest is called with the duration of an attempt, until its
+	// response headers are received, or it fails.
+	ObserveRequest(labels MetricLabels, duration time.Duration)
+}
+
+// multiSink fans out to multiple sinks.
+type multiSink []MetricsSink
+
+// expvarSink exposes the metrics through `expvar`.
+type expvarSink struct {
+	// buckets of the duration histograms, in seconds.
+	buckets []float64
+
+	// durations per method, host, and route.
+	durations *expvar.Map
+
+	// inFlight per host.
+	inFlight *expvar.Map
+
+	// requests per method, host, route, and status class.
+	requests *expvar.Map
+
+	// reqBytes, and respBytes per host.
+	reqBytes  *expvar.Map
+	respBytes *expvar.Map
+
+	// mu guards the creation of histograms.
+	mu sync.Mutex
+}
+
+// countingBody counts the bytes read, reporting them when closed.
+type countingBody struct {
+	io.ReadCloser
+
+	n      int64
+	once   sync.Once
+	report func(n int64)
+}
+
+//////
+// Methods.
+//////
+
+// AddInFlight implements the `MetricsSink` interface.
+func (m multiSink) AddInFlight(labels MetricLabels, delta int64) {
+	for _, sink := range m {
+		sink.AddInFlight(labels, delta)
+	}
+}
+
+// AddRequestBytes implements the `MetricsSink` interface.
+func (m multiSink) AddRequestBytes(labels MetricLabels, n int64) {
+	for _, sink := range m {
+		sink.AddRequestBytes(labels, n)
+	}
+}
+
+// AddResponseBytes implements the `MetricsSink` interface.
+func (m multiSink) AddResponseBytes(labels MetricLabels, n int64) {
+	for _, sink := range m {
+		sink.AddResponseBytes(labels, n)
+	}
+}
+
+// ObserveRequest implements the `MetricsSink` interface.
+func (m multiSink) ObserveRequest(labels MetricLabels, duration time.Duration) {
+	for _, sink := range m {
+		sink.ObserveRequest(labels, duration)
+	}
+}
+
+// AddInFlight implements the `MetricsSink` interface.
+func (s *expvarSink) AddInFlight(labels MetricLabels, delta int64) {
+	s.inFlight.Add(labels.Host, delta)
+}
+
+// AddRequestBytes implements the `MetricsSink` interface.
+func (s *expvarSink) AddRequestBytes(labels MetricLabels, n int64) {
+	s.reqBytes.Add(labels.Host, n)
+}
+
+// AddResponseBytes implements the `MetricsSink` interface.
+func (s *expvarSink) AddResponseBytes(labels MetricLabels, n int64) {
+	s.respBytes.Add(labels.Host, n)
+}
+
+// ObserveRequest implements the `MetricsSink` interface.
+func (s *expvarSink) ObserveRequest(labels MetricLabels, duration time.Duration) {
+	key := strings.Join([]string{labels.Method, labels.Host, labels.Route}, " ")
+
+	s.requests.Add(key+" "+labels.StatusClass, 1)
+
+	s.histogram(key).Observe(duration.Seconds())
+}
+
+// histogram returns the histogram of `key`, creating it if needed.
+func (s *expvarSink) histogram(key string) *metrics.Histogram {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if h, ok := s.durations.Get(key).(*metrics.Histogram); ok {
+		return h
+	}
+
+	h := metrics.NewHistogram(s.buckets)
+
+	s.durations.Set(key, h)
+
+	return h
+}
+
+// Read counts the bytes read.
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	b.n += int64(n)
+
+	return n, err
+}
+
+// Close reports the bytes read, once.
+func (b *countingBody) Close() error {
+	b.once.Do(func() { b.report(b.n) })
+
+	return b.ReadCloser.Close()
+}
+
+// startAttemptMetrics starts measuring an attempt of `req`, returning the
+// function finishing it. The response body, if any, is wrapped, counting the
+// bytes read.
+func (c *Client) startAttemptMetrics(req *http.Request, route string) func(*http.Response, error) *http.Response {
+	labels := MetricLabels{
+		Client: c.Name,
+		Method: req.Method,
+		Host:   req.URL.Host,
+		Route:  routePath(route),
+	}
+
+	c.metricsSink.AddInFlight(labels, 1)
+
+	if req.ContentLength > 0 {
+		c.metricsSink.AddRequestBytes(labels, req.ContentLength)
+	}
+
+	now := time.Now()
+
+	return func(resp *http.Response, err error) *http.Response {
+		duration := time.Since(now)
+
+		c.metricsSink.AddInFlight(labels, -1)
+
+		labels.StatusClass = statusClass(statusCodeOf(resp, err))
+
+		c.metricsSink.ObserveRequest(labels, duration)
+
+		if resp != nil && resp.Body != nil && resp.Body != http.NoBody {
+			resp.Body = &countingBody{
+				ReadCloser: resp.Body,
+				report:     func(n int64) { c.metricsSink.AddResponseBytes(labels, n) },
+			}
+		}
+
+		return resp
+	}
+}
+
+//////
+// Helpers.
+//////
+
+// statusClass returns the class of `statusCode`, e.g.: `2xx`. Zero means no
+// response.
+func statusClass(statusCode int) string {
+	if statusCode <= 0 {
+		return StatusClassError
+	}
+
+	return fmt.Sprintf("%dxx", statusCode/100)
+}
+
+// routePath returns the path of the `route` URL template, e.g.:
+// `http://example.com/users/{id}?a=b` -> `/users/{id}`. Empty if there's no
+// route.
+func routePath(route string) string {
+	if route == "" {
+		return ""
+	}
+
+	u, err := url.Parse(route)
+	if err != nil {
+		return route
+	}
+
+	if u.Path == "" {
+		return "/"
+	}
+
+	return u.Path
+}
+
+// toSeconds converts `buckets` to seconds.
+func toSeconds(buckets []time.Duration) []float64 {
+	seconds := make([]float64, 0, len(buckets))
+
+	for _, bucket := range buckets {
+		seconds = append(seconds, bucket.Seconds())
+	}
+
+	return seconds
+}
+
+//////
+// Factory.
+//////
+
+// newExpvarSink creates the expvar sink of the client `name`.
+func newExpvarSink(prefix, name string, buckets []time.Duration) *expvarSink {
+	return &expvarSink{
+		buckets:   toSeconds(buckets),
+		durations: metrics.NewMap(metricName(prefix, name, "duration.histogram")),
+		inFlight:  metrics.NewMap(metricName(prefix, name, "inflight.host.gauge")),
+		requests:  metrics.NewMap(metricName(prefix, name, "requests", DefaultMetricCounterLabel)),
+		reqBytes:  metrics.NewMap(metricName(prefix, name, "request.bytes", DefaultMetricCounterLabel)),
+		respBytes: metrics.NewMap(metricName(prefix, name, "response.bytes", DefaultMetricCounterLabel)),
+	}
+}
@@ -0,0 +1,179 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testMetricsSink records the metrics it receives.
+type testMetricsSink struct {
+	mu sync.Mutex
+
+	inFlight  int64
+	reqBytes  int64
+	respBytes int64
+	observed  []MetricLabels
+}
+
+func (s *testMetricsSink) AddInFlight(labels MetricLabels, delta int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inFlight += delta
+}
+
+func (s *testMetricsSink) AddRequestBytes(labels MetricLabels, n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reqBytes += n
+}
+
+func (s *testMetricsSink) AddResponseBytes(labels MetricLabels, n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.respBytes += n
+}
+
+func (s *testMetricsSink) ObserveRequest(labels MetricLabels, duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.observed = append(s.observed, labels)
+}
+
+func TestClient_request_metrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/2" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+
+		//nolint:errcheck
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer server.Close()
+
+	sink := &testMetricsSink{}
+
+	c, err := Initialize(
+		WithClientName("metrics"),
+		WithClientLatencyBuckets(time.Millisecond, time.Minute),
+		WithClientMetricsSink(sink),
+	)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var item testJSONItem
+
+	_, err = c.Post(ctx, server.URL+"/users/{id}", WithPathParam("id", "1"), WithReqBody("hello"), WithRespBody(&item))
+	assert.NoError(t, err)
+
+	//nolint:bodyclose
+	_, err = c.Get(ctx, server.URL+"/users/{id}", WithPathParam("id", "2"))
+	assert.True(t, IsNotFound(err))
+
+	host := server.Listener.Addr().String()
+
+	// Custom sink.
+	assert.Equal(t, []MetricLabels{
+		{Client: "metrics", Method: http.MethodPost, Host: host, Route: "/users/{id}", StatusClass: "2xx"},
+		{Client: "metrics", Method: http.MethodGet, Host: host, Route: "/users/{id}", StatusClass: "4xx"},
+	}, sink.observed)
+	assert.Equal(t, int64(0), sink.inFlight)
+	assert.Equal(t, int64(len("hello")), sink.reqBytes)
+	assert.Equal(t, int64(len(`{"id":"1"}`)), sink.respBytes)
+
+	// Expvar.
+	expvar := c.metricsSink.(multiSink)[0].(*expvarSink)
+
+	assert.Equal(t, "1", expvar.requests.Get("POST "+host+" /users/{id} 2xx").String())
+	assert.Equal(t, "1", expvar.requests.Get("GET "+host+" /users/{id} 4xx").String())
+	assert.Equal(t, "0", expvar.inFlight.Get(host).String())
+	assert.Equal(t, "5", expvar.reqBytes.Get(host).String())
+	assert.Equal(t, "10", expvar.respBytes.Get(host).String())
+
+	var histogram struct {
+		Buckets map[string]int `json:"buckets"`
+		Count   int            `json:"count"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(expvar.durations.Get("POST "+host+" /users/{id}").String()), &histogram))
+	assert.Equal(t, 1, histogram.Count)
+	assert.Equal(t, 1, histogram.Buckets["60"])
+	assert.Equal(t, 1, histogram.Buckets["+Inf"])
+}
+
+func TestClient_request_metricsBoundedRoutes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Initialize(WithClientName("metricsboundedroutes"))
+	assert.NoError(t, err)
+
+	for i := 0; i < 50; i++ {
+		resp, err := c.Get(ctx, server.URL+"/users/"+strconv.Itoa(i))
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+	}
+
+	// Raw paths don't create series.
+	sink := c.metricsSink.(multiSink)[0].(*expvarSink)
+
+	keys := 0
+
+	sink.requests.Do(func(kv expvar.KeyValue) { keys++ })
+
+	assert.Equal(t, 1, keys)
+	assert.Equal(t, "50", sink.requests.Get("GET "+server.Listener.Addr().String()+"  2xx").String())
+}
+
+func TestClient_request_metricsTransportError(t *testing.T) {
+	sink := &testMetricsSink{}
+
+	c, err := Initialize(
+		WithClientName("metricstransporterror"),
+		WithClientRetrier(100*time.Millisecond, 1),
+		WithClientMetricsSink(sink),
+	)
+	assert.NoError(t, err)
+
+	//nolint:bodyclose
+	_, err = c.Get(context.Background(), "http://127.0.0.1:1/health")
+	assert.Error(t, err)
+
+	// Retried once.
+	assert.Len(t, sink.observed, 2)
+	assert.Equal(t, StatusClassError, sink.observed[0].StatusClass)
+	// No path params, no route.
+	assert.Equal(t, "", sink.observed[0].Route)
+	assert.Equal(t, int64(0), sink.inFlight)
+}
+
+func TestInitialize_invalidLatencyBuckets(t *testing.T) {
+	_, err := Initialize(
+		WithClientName("metricsinvalidbuckets"),
+		WithClientLatencyBuckets(0),
+	)
+	assert.Error(t, err)
+}
@@ -0,0 +1,38 @@
+package httpclient
+
+import (
+	"net/http"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// RoundTripFunc sends a request, returning its response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a `RoundTripFunc`, allowing to act on the outgoing request
+// (e.g.: signing, header injection), and the response, or error (e.g.:
+// auditing, response rewriting). It's called once per attempt.
+//
+// NOTE: Call `next` to continue the chain, otherwise the request isn't sent.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+//////
+// Helpers.
+//////
+
+// chainMiddlewares wraps `final` with `middlewares`. The first middleware is
+// the outermost one: it's the first to see the request, and the last to see
+// the response.
+func chainMiddlewares(final RoundTripFunc, middlewares ...Middleware) RoundTripFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		if middlewares[i] == nil {
+			continue
+		}
+
+		final = middlewares[i](final)
+	}
+
+	return final
+}
@@ -0,0 +1,123 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Get_middlewares(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+
+	// record creates a middleware recording when it sees the request, and the
+	// response.
+	record := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				calls = append(calls, name+" request")
+				mu.Unlock()
+
+				req.Header.Add("X-Middleware", name)
+
+				resp, err := next(req)
+
+				mu.Lock()
+				calls = append(calls, name+" response")
+				mu.Unlock()
+
+				return resp, err
+			}
+		}
+	}
+
+	var attempts int32
+
+	// First attempt fails, the second succeeds.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, []string{"client1", "client2", "request"}, r.Header.Values("X-Middleware"))
+
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Initialize(
+		WithClientName("middlewares"),
+		WithClientRetrier(100*time.Millisecond, 1),
+		WithClientMiddleware(record("client1"), record("client2")),
+	)
+	assert.NoError(t, err)
+
+	// Response rewriting.
+	rewrite := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			resp, err := next(req)
+			if err == nil {
+				resp.Header.Set("X-Rewritten", "true")
+			}
+
+			return resp, err
+		}
+	}
+
+	resp, err := c.Get(ctx, server.URL, WithMiddleware(record("request"), rewrite))
+	assert.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, "true", resp.Header.Get("X-Rewritten"))
+
+	// Middlewares run per attempt, in order.
+	attempt := []string{
+		"client1 request",
+		"client2 request",
+		"request request",
+		"request response",
+		"client2 response",
+		"client1 response",
+	}
+
+	assert.Equal(t, append(attempt, attempt...), calls)
+}
+
+func TestClient_Get_middlewareShortCircuit(t *testing.T) {
+	c, err := Initialize(WithClientName("middlewareshortcircuit"))
+	assert.NoError(t, err)
+
+	// Never calls `next`, so no request is sent.
+	short := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       http.NoBody,
+				Request:    req,
+			}, nil
+		}
+	}
+
+	resp, err := c.Get(context.Background(), "http://localhost:1", WithMiddleware(short))
+	assert.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -4,13 +4,16 @@ import (
 	"bytes"
 	"encoding/base64"
 	"io"
+	"net/http"
 	"net/url"
-	"os"
 	"strings"
+	"time"
 
 	"github.com/thalesfsp/customerror"
+	"github.com/thalesfsp/sypl"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 
-	"github.com/thalesfsp/httpclient/internal/logging"
 	"github.com/thalesfsp/httpclient/internal/shared"
 )
 
@@ -26,6 +29,12 @@ type Options struct {
 	// Headers of the request.
 	Headers map[string]string `json:"headers"`
 
+	// Middlewares of the request, they run after the client's ones.
+	Middlewares []Middleware `json:"-"`
+
+	// PathParams of the request, filling placeholders, e.g.: `{id}`.
+	PathParams map[string]string `json:"pathParams"`
+
 	// QueryParams of the request.
 	QueryParams map[string]string `json:"queryParams"`
 
@@ -35,6 +44,36 @@ type Options struct {
 	// RespBody is the response body.
 	RespBody any `json:"respBody"`
 
+	// ErrBody is where non-2xx response bodies are decoded into.
+	ErrBody any `json:"errBody"`
+
+	// ExpectedStatus are the only status codes not turned into errors.
+	ExpectedStatus []int `json:"expectedStatus"`
+
+	// NoStatusError disables turning status codes into errors.
+	NoStatusError bool `json:"noStatusError"`
+
+	// RedirectIsError overrides the client's `RedirectIsError`.
+	RedirectIsError *bool `json:"redirectIsError"`
+
+	// Hedging of the request, if set.
+	Hedging *HedgingConfig `json:"hedging"`
+
+	// Backoff overrides the client's backoff.
+	Backoff Backoff `json:"-"`
+
+	// RetrierMaxBudget overrides the client's max total wait across retries.
+	RetrierMaxBudget time.Duration `json:"retrierMaxBudget"`
+
+	// RetrierMaxWait overrides the client's max wait between retries.
+	RetrierMaxWait time.Duration `json:"retrierMaxWait"`
+
+	// RetryPolicy overrides the client's retry policy.
+	RetryPolicy RetryPolicy `json:"-"`
+
+	// baseReq is the caller-built request, see `DoRequest`.
+	baseReq *http.Request
+
 	reqBodyAsIOReader io.Reader `json:"-"`
 }
 
@@ -96,11 +135,45 @@ func WithQueryParam(k, v string) Func {
 	}
 }
 
+// WithPathParam fills the `{k}` placeholder in the request's URL path with
+// the escaped `v`, e.g.: `/users/{id}`.
+//
+// NOTE: If path params are set, or there's a base URL, placeholders left
+// unfilled are an error. Otherwise, braces are sent as they are.
+func WithPathParam(k, v string) Func {
+	return func(o *Options) error {
+		if k == "" || v == "" {
+			return nil
+		}
+
+		o.PathParams[k] = v
+
+		return nil
+	}
+}
+
+// WithMiddleware adds middlewares to the request. They run in order, after the
+// client's ones.
+func WithMiddleware(middlewares ...Middleware) Func {
+	return func(o *Options) error {
+		o.Middlewares = append(o.Middlewares, middlewares...)
+
+		return nil
+	}
+}
+
 // WithReqBody set the request's body. Processing rule:
 //
 // - If it's a string, then use it as is.
 // - If it's an io.Reader, then use it as is.
 // - If it's anything else, then marshal it and use it as is.
+//
+// NOTE: The body is logged redacted, see `Redactor`.
+//
+// NOTE: Retries resend the body. It works out of the box for everything but
+// non-seekable `io.Reader`s, which fail with `ErrReqBodyNotReplayable` if a
+// retry is needed. Seekable files, e.g.: `*os.File`, are closed once the last
+// attempt is done.
 func WithReqBody(body interface{}) Func {
 	return func(o *Options) error {
 		if body == nil {
@@ -111,16 +184,10 @@ func WithReqBody(body interface{}) Func {
 
 		switch b := body.(type) {
 		case string:
-			logging.Get().Debuglnf("request body: %s", b)
-
 			bodyReader = strings.NewReader(b)
 		case io.Reader:
-			logging.Get().Debuglnf("request body: %+v", bodyReader)
-
 			bodyReader = b
 		case url.Values:
-			logging.Get().Debuglnf("request body: %s", b.Encode())
-
 			bodyReader = strings.NewReader(b.Encode())
 
 			// Initalize o.Headers if needed.
@@ -136,11 +203,11 @@ func WithReqBody(body interface{}) Func {
 				)
 			}
 
-			logging.Get().Debuglnf("request body: %s", string(bodyBytes))
-
 			bodyReader = bytes.NewReader(bodyBytes)
 		}
 
+		// Kept for logging, redacted, see `Redactor`.
+		o.ReqBody = body
 		o.reqBodyAsIOReader = bodyReader
 
 		return nil
@@ -148,6 +215,9 @@ func WithReqBody(body interface{}) Func {
 }
 
 // WithRespBody set the response's body.
+//
+// NOTE: An empty response body, e.g.: `204`, leaves it untouched. If decoding
+// fails, the error carries the raw payload in the `respBody` field.
 func WithRespBody(body interface{}) Func {
 	return func(o *Options) error {
 		if body == nil {
@@ -160,14 +230,226 @@ func WithRespBody(body interface{}) Func {
 	}
 }
 
+// WithErrBody set where non-2xx response bodies are decoded into, e.g.: a
+// pointer to the API error struct. The request still errors.
+//
+// NOTE: If decoding fails, e.g.: the body isn't JSON, it's left untouched. The
+// raw payload is available in the returned error.
+func WithErrBody(body any) Func {
+	return func(o *Options) error {
+		if body == nil {
+			return nil
+		}
+
+		o.ErrBody = body
+
+		return nil
+	}
+}
+
+// WithExpectedStatus set the only status codes not turned into errors, e.g.:
+// `WithExpectedStatus(200, 404)`. Any other, including 2xx, errors.
+//
+// NOTE: Non-2xx expected responses are returned with the body intact, and
+// aren't decoded into `RespBody`. Set `WithErrBody` to decode them. Also, as
+// they aren't errors, they aren't retried.
+func WithExpectedStatus(statusCodes ...int) Func {
+	return func(o *Options) error {
+		o.ExpectedStatus = append(o.ExpectedStatus, statusCodes...)
+
+		return nil
+	}
+}
+
+// WithNoStatusError disables turning status codes into errors. Only transport
+// errors error.
+//
+// NOTE: See `WithExpectedStatus` notes.
+func WithNoStatusError() Func {
+	return func(o *Options) error {
+		o.NoStatusError = true
+
+		return nil
+	}
+}
+
+// WithHedging set the request to be hedged: if an attempt hasn't answered
+// after `delay`, another one is fired, up to `maxAttempts` concurrent ones.
+// The first successful response wins, and the others are canceled. Failures
+// aren't hedged, they're up to the retry policy, see `WithRetryPolicy`.
+//
+// NOTE: Only idempotent methods are hedged, see `WithHedgingAnyMethod`. Also,
+// requests which body can't be replayed concurrently, e.g.: a plain
+// `io.Reader`, aren't hedged. Each hedged attempt waits for its own rate
+// limit token, and bulkhead slot, and is measured on its own.
+func WithHedging(delay time.Duration, maxAttempts int) Func {
+	return func(o *Options) error {
+		if o.Hedging == nil {
+			o.Hedging = &HedgingConfig{}
+		}
+
+		o.Hedging.Delay = delay
+		o.Hedging.MaxAttempts = maxAttempts
+
+		return nil
+	}
+}
+
+// WithHedgingAnyMethod allows hedging non-idempotent methods, e.g.: `POST`.
+//
+// WARN: Only use it if the server handles duplicated requests.
+func WithHedgingAnyMethod() Func {
+	return func(o *Options) error {
+		if o.Hedging == nil {
+			o.Hedging = &HedgingConfig{}
+		}
+
+		o.Hedging.AnyMethod = true
+
+		return nil
+	}
+}
+
+// WithRedirectIsError overrides, for the request, whether final `3xx`
+// responses are turned into errors.
+func WithRedirectIsError(redirectIsError bool) Func {
+	return func(o *Options) error {
+		o.RedirectIsError = &redirectIsError
+
+		return nil
+	}
+}
+
+// WithRetryPolicy overrides, for the request, the client's retry policy.
+func WithRetryPolicy(policy RetryPolicy) Func {
+	return func(o *Options) error {
+		if policy == nil {
+			return nil
+		}
+
+		o.RetryPolicy = policy
+
+		return nil
+	}
+}
+
+// WithBackoff overrides, for the request, the client's backoff.
+func WithBackoff(backoff Backoff) Func {
+	return func(o *Options) error {
+		if backoff == nil {
+			return nil
+		}
+
+		o.Backoff = backoff
+
+		return nil
+	}
+}
+
+// WithRetrierMaxWait overrides, for the request, the client's max wait between
+// retries.
+func WithRetrierMaxWait(maxWait time.Duration) Func {
+	return func(o *Options) error {
+		o.RetrierMaxWait = maxWait
+
+		return nil
+	}
+}
+
+// WithRetrierMaxBudget overrides, for the request, the client's max total wait
+// across retries.
+func WithRetrierMaxBudget(maxBudget time.Duration) Func {
+	return func(o *Options) error {
+		o.RetrierMaxBudget = maxBudget
+
+		return nil
+	}
+}
+
 //////
 // Client's specific options.
 //////
 
 // ClientOptions options specific to setting up the client.
 type ClientOptions struct {
+	// Backoff computes the wait between retries.
+	Backoff Backoff
+
+	// BaseURL relative request URLs are resolved against.
+	BaseURL string
+
+	// Bulkhead limits the number of in-flight requests.
+	Bulkhead *BulkheadConfig
+
+	// CircuitBreaker configures the per host circuit breaker.
+	CircuitBreaker *CircuitBreakerConfig
+
+	// Headers are the default headers for all requests.
+	Headers map[string]string
+
+	// LatencyBuckets are the upper bounds of the request duration histogram
+	// buckets. Defaults to `DefaultLatencyBuckets`.
+	LatencyBuckets []time.Duration
+
+	// Logger of the HTTP client.
+	Logger sypl.ISypl
+
+	// MetricsPrefix is the prefix of the metric names.
+	MetricsPrefix string
+
+	// MetricsSinks receive the request metrics, in addition to `expvar`.
+	MetricsSinks []MetricsSink
+
+	// Middlewares wrap every attempt of every request.
+	Middlewares []Middleware
+
 	// Name of the HTTP client.
 	Name string
+
+	// Propagator injects the trace context into the outgoing headers. Defaults
+	// to W3C Trace Context, e.g.: `traceparent`, and `tracestate`.
+	Propagator propagation.TextMapPropagator
+
+	// RateLimit configures the client-side rate limiter.
+	RateLimit *RateLimitConfig
+
+	// RedirectIsError turns final `3xx` responses into errors.
+	RedirectIsError bool
+
+	// Redactor redacts secrets before they are logged. Defaults to the default
+	// deny-lists, see `NewRedactor`.
+	Redactor *Redactor
+
+	// RetrierBackoffDuration is the initial wait between retries.
+	RetrierBackoffDuration time.Duration
+
+	// RetrierBackoffTimes is the max number of retries.
+	RetrierBackoffTimes int
+
+	// RetrierMaxBudget caps the total wait across all retries of a request.
+	RetrierMaxBudget time.Duration
+
+	// RetrierMaxWait caps the wait between retries.
+	RetrierMaxWait time.Duration
+
+	// RetryPolicy decides which failed attempts are retried.
+	RetryPolicy RetryPolicy
+
+	// Timeout of the underlying HTTP client.
+	Timeout time.Duration
+
+	// TracerProvider enables OpenTelemetry tracing.
+	TracerProvider trace.TracerProvider
+
+	// TLSConfig configures TLS, including mTLS.
+	TLSConfig *TLSConfig
+
+	// Transport of the underlying HTTP client. Takes precedence over
+	// `TransportConfig`.
+	Transport http.RoundTripper
+
+	// TransportConfig tunes the default transport, and its connection pool.
+	TransportConfig *TransportConfig
 }
 
 // ClientFunc defines the function signature for setting up the client.
@@ -182,10 +464,311 @@ func WithClientName(name string) ClientFunc {
 	}
 }
 
-// WithPrefix set the prefix of the HTTP metrics.
+// WithClientBaseURL set the base URL relative request URLs are resolved
+// against.
+func WithClientBaseURL(baseURL string) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.BaseURL = baseURL
+
+		return nil
+	}
+}
+
+// WithClientHeader add a key value pair to the default headers.
+func WithClientHeader(k, v string) ClientFunc {
+	return func(o *ClientOptions) error {
+		if k == "" || v == "" {
+			return nil
+		}
+
+		if o.Headers == nil {
+			o.Headers = make(map[string]string)
+		}
+
+		o.Headers[k] = v
+
+		return nil
+	}
+}
+
+// WithClientHeaders add the key value pairs to the default headers.
+func WithClientHeaders(headers map[string]string) ClientFunc {
+	return func(o *ClientOptions) error {
+		if o.Headers == nil {
+			o.Headers = make(map[string]string)
+		}
+
+		for k, v := range headers {
+			o.Headers[k] = v
+		}
+
+		return nil
+	}
+}
+
+// WithClientTimeout set the timeout of the HTTP client.
+func WithClientTimeout(timeout time.Duration) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.Timeout = timeout
+
+		return nil
+	}
+}
+
+// WithClientRetrier set the initial wait between retries, and the max number
+// of retries.
+func WithClientRetrier(backoffDuration time.Duration, backoffTimes int) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.RetrierBackoffDuration = backoffDuration
+		o.RetrierBackoffTimes = backoffTimes
+
+		return nil
+	}
+}
+
+// WithClientRetrierMaxWait set the max wait between retries.
+func WithClientRetrierMaxWait(maxWait time.Duration) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.RetrierMaxWait = maxWait
+
+		return nil
+	}
+}
+
+// WithClientRetrierMaxBudget set the max total wait across retries.
+func WithClientRetrierMaxBudget(maxBudget time.Duration) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.RetrierMaxBudget = maxBudget
+
+		return nil
+	}
+}
+
+// WithClientBackoff set the backoff of the HTTP client.
+func WithClientBackoff(backoff Backoff) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.Backoff = backoff
+
+		return nil
+	}
+}
+
+// WithClientRetryPolicy set the retry policy of the HTTP client.
+func WithClientRetryPolicy(policy RetryPolicy) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.RetryPolicy = policy
+
+		return nil
+	}
+}
+
+// WithClientTransport set the transport of the underlying HTTP client.
+func WithClientTransport(transport http.RoundTripper) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.Transport = transport
+
+		return nil
+	}
+}
+
+// WithClientTransportConfig tunes the transport of the underlying HTTP client,
+// and its connection pool. It's ignored if a transport is set via
+// `WithClientTransport`.
+func WithClientTransportConfig(cfg TransportConfig) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.TransportConfig = &cfg
+
+		return nil
+	}
+}
+
+// WithClientTLSConfig configures TLS, e.g.: custom CAs, client certificates
+// for mTLS, min TLS version, and server name.
+func WithClientTLSConfig(cfg TLSConfig) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.TLSConfig = &cfg
+
+		return nil
+	}
+}
+
+// WithClientMiddleware adds middlewares to the HTTP client. They run in
+// order, before per-request ones.
+func WithClientMiddleware(middlewares ...Middleware) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.Middlewares = append(o.Middlewares, middlewares...)
+
+		return nil
+	}
+}
+
+// WithClientLogger set the logger of the HTTP client.
+func WithClientLogger(logger sypl.ISypl) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.Logger = logger
+
+		return nil
+	}
+}
+
+// WithClientBulkhead limits the number of in-flight requests, per client,
+// and per host. Requests wait for a slot up to the queue timeout, failing with
+// `ErrBulkheadTimeout`. See `BulkheadConfig`.
+func WithClientBulkhead(config BulkheadConfig) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.Bulkhead = &config
+
+		return nil
+	}
+}
+
+// WithClientCircuitBreaker set a per host circuit breaker. While open,
+// requests fail with `ErrCircuitOpen`, without touching the network. See
+// `CircuitBreakerConfig`.
+func WithClientCircuitBreaker(config CircuitBreakerConfig) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.CircuitBreaker = &config
+
+		return nil
+	}
+}
+
+// WithClientRateLimit set a client-side, token bucket, rate limiter. Every
+// attempt, retries included, waits for a token, respecting the request
+// context. See `RateLimitConfig`.
+func WithClientRateLimit(config RateLimitConfig) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.RateLimit = &config
+
+		return nil
+	}
+}
+
+// WithClientRedirectIsError set whether final `3xx` responses, e.g.: not
+// followed redirects, are turned into errors.
+func WithClientRedirectIsError(redirectIsError bool) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.RedirectIsError = redirectIsError
+
+		return nil
+	}
+}
+
+// WithClientRedactor set the redactor of the HTTP client, replacing the
+// default one.
+//
+// NOTE: An empty `Redactor{}` disables redaction.
+func WithClientRedactor(redactor *Redactor) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.Redactor = redactor
+
+		return nil
+	}
+}
+
+// WithClientLatencyBuckets set the upper bounds of the request duration
+// histogram buckets, e.g.: `WithClientLatencyBuckets(100*time.Millisecond,
+// time.Second)`.
+func WithClientLatencyBuckets(buckets ...time.Duration) ClientFunc {
+	return func(o *ClientOptions) error {
+		for _, bucket := range buckets {
+			if bucket <= 0 {
+				return customerror.NewInvalidError("latency buckets, they must be positive")
+			}
+		}
+
+		o.LatencyBuckets = buckets
+
+		return nil
+	}
+}
+
+// WithClientMetricsSink adds a sink receiving the request metrics, e.g.: to
+// export them to Prometheus, or StatsD. Metrics are still exposed through
+// `expvar`.
+func WithClientMetricsSink(sink MetricsSink) ClientFunc {
+	return func(o *ClientOptions) error {
+		if sink == nil {
+			return nil
+		}
+
+		o.MetricsSinks = append(o.MetricsSinks, sink)
+
+		return nil
+	}
+}
+
+// WithClientTracerProvider enables OpenTelemetry tracing: an internal span per
+// request, and a child client one per attempt, with semantic-convention
+// attributes.
+// The trace context is injected into the outgoing headers, see
+// `WithClientPropagator`.
+func WithClientTracerProvider(tracerProvider trace.TracerProvider) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.TracerProvider = tracerProvider
+
+		return nil
+	}
+}
+
+// WithClientPropagator set the propagator injecting the trace context into the
+// outgoing headers. Defaults to W3C Trace Context.
+//
+// NOTE: Only used if tracing is enabled, see `WithClientTracerProvider`.
+func WithClientPropagator(propagator propagation.TextMapPropagator) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.Propagator = propagator
+
+		return nil
+	}
+}
+
+// WithClientRedactedHeaders adds headers to be redacted from logs.
+func WithClientRedactedHeaders(headers ...string) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.redactor().Headers = append(o.redactor().Headers, headers...)
+
+		return nil
+	}
+}
+
+// WithClientRedactedQueryParams adds query params, and form fields to be
+// redacted from logs.
+func WithClientRedactedQueryParams(queryParams ...string) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.redactor().QueryParams = append(o.redactor().QueryParams, queryParams...)
+
+		return nil
+	}
+}
+
+// WithClientRedactedJSONFields adds JSON fields, names or paths (e.g.:
+// `user.password`), to be redacted from logs.
+func WithClientRedactedJSONFields(jsonFields ...string) ClientFunc {
+	return func(o *ClientOptions) error {
+		o.redactor().JSONFields = append(o.redactor().JSONFields, jsonFields...)
+
+		return nil
+	}
+}
+
+// redactor returns the redactor, initializing it with the defaults if needed.
+func (o *ClientOptions) redactor() *Redactor {
+	if o.Redactor == nil {
+		o.Redactor = NewRedactor(nil, nil, nil)
+	}
+
+	return o.Redactor
+}
+
+// WithPrefix set the prefix of the client metric names, e.g.:
+// `{prefix}.httpclient.{name}.failed.counter`, and `{prefix}_httpclient_*` for
+// Prometheus.
+//
+// NOTE: The `HTTPCLIENT_METRICS_PREFIX` env var isn't used anymore.
 func WithPrefix(prefix string) ClientFunc {
 	return func(o *ClientOptions) error {
-		os.Setenv("HTTPCLIENT_METRICS_PREFIX", prefix)
+		o.MetricsPrefix = prefix
 
 		return nil
 	}
@@ -2,11 +2,303 @@ package httpclient
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
+	"sync"
 
 	"github.com/thalesfsp/concurrentloop"
 )
 
+//////
+// Vars, consts, and types.
+//////
+
+// DefaultParallelMaxConcurrency is the default max number of concurrent
+// requests of `ParallelDo`.
+const DefaultParallelMaxConcurrency = 10
+
+// RequestSpec describes a request of `ParallelDo`.
+type RequestSpec struct {
+	// Method of the request.
+	Method string
+
+	// URL of the request.
+	URL string
+
+	// Options of the request.
+	Options []Func
+
+	// RespBody is where the response body is decoded into, see `WithRespBody`.
+	RespBody any
+}
+
+// ParallelOptions contains the `ParallelDo` options.
+type ParallelOptions struct {
+	// FailFast stops at the first error, canceling the in-flight requests, and
+	// not starting the remaining ones. Otherwise, all results are collected.
+	FailFast bool
+
+	// MaxConcurrency is the max number of concurrent requests. Defaults to
+	// `DefaultParallelMaxConcurrency`. Zero, or negative, means no limit.
+	MaxConcurrency int
+}
+
+// ParallelFunc allows to set `ParallelDo` options.
+type ParallelFunc func(o *ParallelOptions)
+
+//////
+// Exported built-in options.
+//////
+
+// WithParallelFailFast stops `ParallelDo` at the first error.
+func WithParallelFailFast() ParallelFunc {
+	return func(o *ParallelOptions) {
+		o.FailFast = true
+	}
+}
+
+// WithParallelMaxConcurrency set the max number of concurrent requests of
+// `ParallelDo`.
+func WithParallelMaxConcurrency(maxConcurrency int) ParallelFunc {
+	return func(o *ParallelOptions) {
+		o.MaxConcurrency = maxConcurrency
+	}
+}
+
+//////
+// Methods.
+//////
+
+// newParallelOptions creates the options for `count` requests.
+func newParallelOptions(count int, o ...ParallelFunc) *ParallelOptions {
+	options := &ParallelOptions{
+		MaxConcurrency: DefaultParallelMaxConcurrency,
+	}
+
+	for _, opt := range o {
+		opt(options)
+	}
+
+	if options.MaxConcurrency <= 0 || options.MaxConcurrency > count {
+		options.MaxConcurrency = count
+	}
+
+	return options
+}
+
+// ParallelDo concurrently does the requests described by `specs`, at most
+// `MaxConcurrency` at a time. Results are in input order. The error joins all
+// errors, or, in fail-fast mode, is the first one.
+//
+// NOTE: In fail-fast mode, the first error cancels the in-flight requests.
+// Requests not started get the context error.
+//
+// WARN: IT'S THE CALLER'S RESPONSIBILITY TO CLOSE THE BODIES, unless decoded,
+// see `RespBody`. Expected non-2xx ones, see `WithExpectedStatus`, aren't.
+func (c *Client) ParallelDo(
+	ctx context.Context,
+	specs []RequestSpec,
+	o ...ParallelFunc,
+) ([]RequestResult, error) {
+	results := make([]RequestResult, len(specs))
+
+	// Each index is written once, by a single worker.
+	firstErr := c.parallel(ctx, specs, newParallelOptions(len(specs), o...), func(result RequestResult) {
+		results[result.Index] = result
+	})
+
+	if firstErr != nil {
+		return results, firstErr
+	}
+
+	errs := make([]error, 0, len(results))
+
+	for _, result := range results {
+		errs = append(errs, result.Error)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// ParallelDoStream is like `ParallelDo`, but results are sent, as soon as
+// each request finishes, to the returned channel. `Index` tells which spec
+// it's the result of. The channel is closed when all requests are done.
+//
+// NOTE: If `ctx` is canceled, no request is started anymore, and results not
+// received yet may be dropped, closing their bodies.
+//
+// WARN: IT'S THE CALLER'S RESPONSIBILITY TO CLOSE THE BODIES, unless decoded,
+// see `RespBody`. Expected non-2xx ones, see `WithExpectedStatus`, aren't.
+func (c *Client) ParallelDoStream(
+	ctx context.Context,
+	specs []RequestSpec,
+	o ...ParallelFunc,
+) <-chan RequestResult {
+	options := newParallelOptions(len(specs), o...)
+
+	results := make(chan RequestResult, options.MaxConcurrency)
+
+	go func() {
+		defer close(results)
+
+		//nolint:errcheck
+		c.parallel(ctx, specs, options, func(result RequestResult) {
+			select {
+			case results <- result:
+			case <-ctx.Done():
+				// Nobody may be receiving anymore.
+				if result.Response != nil && result.Response.Body != nil {
+					result.Response.Body.Close()
+				}
+			}
+		})
+	}()
+
+	return results
+}
+
+// parallel concurrently does the requests described by `specs`, calling `emit`
+// with each result. In fail-fast mode, it returns the first error.
+func (c *Client) parallel(
+	ctx context.Context,
+	specs []RequestSpec,
+	options *ParallelOptions,
+	emit func(result RequestResult),
+) error {
+	var (
+		firstErr error
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+
+		// In fail-fast mode, the first error cancels the in-flight requests.
+		inFlight = make(map[int]context.CancelFunc)
+	)
+
+	indexes := make(chan int)
+
+	// Bounded number of workers.
+	for i := 0; i < options.MaxConcurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for index := range indexes {
+				mu.Lock()
+
+				if firstErr != nil || ctx.Err() != nil {
+					mu.Unlock()
+
+					err := ctx.Err()
+					if err == nil {
+						err = context.Canceled
+					}
+
+					emit(RequestResult{Error: err, Index: index})
+
+					continue
+				}
+
+				// Each request has its own context, canceled once its body is
+				// closed, so the remaining ones can be canceled, without
+				// affecting the finished ones.
+				reqCtx, reqCancel := context.WithCancel(ctx)
+
+				inFlight[index] = reqCancel
+
+				mu.Unlock()
+
+				//nolint:bodyclose
+				resp, err := c.doSpec(reqCtx, specs[index])
+
+				mu.Lock()
+
+				delete(inFlight, index)
+
+				if err != nil && options.FailFast && firstErr == nil {
+					firstErr = err
+
+					for _, cancel := range inFlight {
+						cancel()
+					}
+				}
+
+				mu.Unlock()
+
+				emit(RequestResult{
+					Error:    err,
+					Index:    index,
+					Response: cancelOnClose(resp, err, reqCancel),
+				})
+			}
+		}()
+	}
+
+	for index := range specs {
+		indexes <- index
+	}
+
+	close(indexes)
+
+	wg.Wait()
+
+	return firstErr
+}
+
+// doSpec does the request described by `spec`.
+func (c *Client) doSpec(ctx context.Context, spec RequestSpec) (*http.Response, error) {
+	opts := make([]Func, 0, len(spec.Options)+1)
+	opts = append(opts, spec.Options...)
+
+	if spec.RespBody != nil {
+		opts = append(opts, WithRespBody(spec.RespBody))
+	}
+
+	return c.request(ctx, spec.Method, spec.URL, opts...)
+}
+
+// cancelOnClose makes closing the `resp` body cancel its context. If there's
+// no body to be closed, or it was already released, e.g.: decoded, see
+// `WithRespBody`, it's canceled right away.
+func cancelOnClose(resp *http.Response, err error, cancel context.CancelFunc) *http.Response {
+	if err != nil || resp == nil || resp.Body == nil {
+		cancel()
+
+		return resp
+	}
+
+	if _, ok := resp.Body.(releasedBody); ok {
+		cancel()
+
+		return resp
+	}
+
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp
+}
+
+// releasedBody is a response body which doesn't need its connection anymore:
+// it was read, and closed by the client, or buffered in memory.
+type releasedBody struct {
+	io.ReadCloser
+}
+
+// cancelOnCloseBody is a body which cancels its request context when closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+
+	cancel context.CancelFunc
+}
+
+// Close implements the io.Closer interface.
+func (b *cancelOnCloseBody) Close() error {
+	defer b.cancel()
+
+	return b.ReadCloser.Close()
+}
+
 // ParallelGet concurrently call GET on the given URLs.
 //
 // NOTE: Both `Content-shared.PackageName` and `Accept` are already set to `application/json`.
@@ -1,9 +1,13 @@
 package httpclient
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -157,3 +161,237 @@ func TestClient_ParallelGet2(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_ParallelDo(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			previous := atomic.LoadInt32(&maxInFlight)
+			if current <= previous || atomic.CompareAndSwapInt32(&maxInFlight, previous, current) {
+				break
+			}
+		}
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if r.URL.Path == "/large" {
+			w.WriteHeader(http.StatusNotFound)
+
+			// Streamed, so it's still being read after the request returns.
+			for i := 0; i < 16; i++ {
+				if _, err := w.Write(bytes.Repeat([]byte("a"), 1<<16)); err != nil {
+					return
+				}
+
+				w.(http.Flusher).Flush()
+
+				time.Sleep(5 * time.Millisecond)
+			}
+
+			return
+		}
+
+		time.Sleep(50 * time.Millisecond)
+
+		if _, err := w.Write([]byte(`{"id":"` + r.Method + r.URL.Path + `"}`)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	c, err := Initialize(WithClientName("paralleldo"), WithClientBaseURL(server.URL))
+	assert.NoError(t, err)
+
+	t.Run("should work - bounded, and in order", func(t *testing.T) {
+		atomic.StoreInt32(&maxInFlight, 0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		specs := make([]RequestSpec, 0, 20)
+		items := make([]testJSONItem, 20)
+
+		for i := range items {
+			method := http.MethodGet
+			if i%2 == 0 {
+				method = http.MethodPost
+			}
+
+			specs = append(specs, RequestSpec{
+				Method:   method,
+				URL:      "/" + strconv.Itoa(i),
+				Options:  []Func{WithHeader("X-Index", strconv.Itoa(i))},
+				RespBody: &items[i],
+			})
+		}
+
+		results, err := c.ParallelDo(ctx, specs, WithParallelMaxConcurrency(3))
+		assert.NoError(t, err)
+		assert.Len(t, results, len(specs))
+
+		for i, result := range results {
+			assert.NoError(t, result.Error)
+			assert.Equal(t, specs[i].Method+specs[i].URL, items[i].ID)
+		}
+
+		assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(3))
+	})
+
+	t.Run("should work - collect all", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		results, err := c.ParallelDo(ctx, []RequestSpec{
+			{Method: http.MethodGet, URL: "/fail"},
+			{Method: http.MethodGet, URL: "/ok", RespBody: &testJSONItem{}},
+		})
+		assert.Error(t, err)
+		assert.True(t, IsBadRequest(results[0].Error))
+		assert.NoError(t, results[1].Error)
+	})
+
+	t.Run("should work - fail fast", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		specs := []RequestSpec{{Method: http.MethodGet, URL: "/fail"}}
+
+		for i := 0; i < 5; i++ {
+			specs = append(specs, RequestSpec{Method: http.MethodGet, URL: "/ok", RespBody: &testJSONItem{}})
+		}
+
+		results, err := c.ParallelDo(ctx, specs, WithParallelFailFast(), WithParallelMaxConcurrency(1))
+		assert.True(t, IsBadRequest(err))
+
+		for _, result := range results[1:] {
+			assert.ErrorIs(t, result.Error, context.Canceled)
+		}
+	})
+
+	t.Run("should work - decoded request context is released", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		tests := []struct {
+			name string
+			spec RequestSpec
+		}{
+			{
+				name: "spec RespBody",
+				spec: RequestSpec{Method: http.MethodGet, URL: "/ok", RespBody: &testJSONItem{}},
+			},
+			{
+				name: "WithRespBody option",
+				spec: RequestSpec{Method: http.MethodGet, URL: "/ok", Options: []Func{WithRespBody(&testJSONItem{})}},
+			},
+		}
+		for _, tt := range tests {
+			var reqCtx context.Context
+
+			capture := func(next RoundTripFunc) RoundTripFunc {
+				return func(req *http.Request) (*http.Response, error) {
+					reqCtx = req.Context()
+
+					return next(req)
+				}
+			}
+
+			tt.spec.Options = append(tt.spec.Options, WithMiddleware(capture))
+
+			_, err := c.ParallelDo(ctx, []RequestSpec{tt.spec})
+			assert.NoError(t, err, tt.name)
+			assert.ErrorIs(t, reqCtx.Err(), context.Canceled, tt.name)
+		}
+	})
+
+	t.Run("should work - expected status body left open", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		results, err := c.ParallelDo(ctx, []RequestSpec{{
+			Method:   http.MethodGet,
+			URL:      "/large",
+			Options:  []Func{WithExpectedStatus(http.StatusOK, http.StatusNotFound)},
+			RespBody: &testJSONItem{},
+		}})
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, results[0].Response.StatusCode)
+		assert.Len(t, readBody(t, results[0].Response), 1<<20)
+	})
+
+	t.Run("should work - response body left open", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		results, err := c.ParallelDo(ctx, []RequestSpec{{Method: http.MethodGet, URL: "/open"}})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"id":"GET/open"}`, readBody(t, results[0].Response))
+	})
+}
+
+func TestClient_ParallelDoStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-time.After(2 * time.Second):
+			case <-r.Context().Done():
+			}
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := Initialize(WithClientName("paralleldostream"), WithClientBaseURL(server.URL))
+	assert.NoError(t, err)
+
+	t.Run("should work - fast results first", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		specs := []RequestSpec{
+			{Method: http.MethodGet, URL: "/slow"},
+			{Method: http.MethodGet, URL: "/fast"},
+			{Method: http.MethodGet, URL: "/fast"},
+		}
+
+		indexes := []int{}
+
+		for result := range c.ParallelDoStream(ctx, specs) {
+			assert.NoError(t, result.Error)
+			assert.NoError(t, result.Response.Body.Close())
+
+			indexes = append(indexes, result.Index)
+		}
+
+		assert.ElementsMatch(t, []int{0, 1, 2}, indexes)
+		assert.Equal(t, 0, indexes[len(indexes)-1])
+	})
+
+	t.Run("should work - stops on context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		specs := make([]RequestSpec, 0, 10)
+
+		for i := 0; i < 10; i++ {
+			specs = append(specs, RequestSpec{Method: http.MethodGet, URL: "/slow"})
+		}
+
+		now := time.Now()
+
+		for result := range c.ParallelDoStream(ctx, specs, WithParallelMaxConcurrency(2)) {
+			assert.Error(t, result.Error)
+		}
+
+		assert.Less(t, time.Since(now), 2*time.Second)
+	})
+}
@@ -0,0 +1,148 @@
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// Attempt describes a finished, failed, request attempt.
+type Attempt struct {
+	// Error is the attempt's error.
+	Error error
+
+	// Method is the request's HTTP method.
+	Method string
+
+	// Number is the attempt number, starting at 1.
+	Number int
+
+	// Response is the attempt's response. It's nil for transport errors, e.g.:
+	// timeouts, connection resets, DNS failures.
+	//
+	// NOTE: The body was already consumed.
+	Response *http.Response
+
+	// URL is the request's URL.
+	URL string
+}
+
+// RetryPolicy decides if a failed attempt should be retried. How many times,
+// and how long to wait is up to the backoff.
+type RetryPolicy interface {
+	// ShouldRetry returns true if the attempt should be retried.
+	ShouldRetry(attempt *Attempt) bool
+}
+
+// RetryPolicyFunc allows to use a function as a `RetryPolicy`.
+type RetryPolicyFunc func(attempt *Attempt) bool
+
+// ShouldRetry implements the RetryPolicy interface.
+func (f RetryPolicyFunc) ShouldRetry(attempt *Attempt) bool {
+	return f(attempt)
+}
+
+var (
+	// DefaultRetryPolicy retries `429`, and `5xx`.
+	DefaultRetryPolicy RetryPolicy = HTTPStatusCodeClassifier{Regex: httpRetrierRegex}
+
+	// NeverRetryPolicy never retries.
+	NeverRetryPolicy RetryPolicy = RetryPolicyFunc(func(attempt *Attempt) bool {
+		return false
+	})
+
+	// TransientErrorRetryPolicy retries transport errors likely to go away:
+	// timeouts, connection resets, refused connections, and DNS failures other
+	// than "host not found".
+	TransientErrorRetryPolicy RetryPolicy = RetryPolicyFunc(func(attempt *Attempt) bool {
+		return attempt.Response == nil &&
+			(IsTimeoutError(attempt.Error) ||
+				IsConnectionError(attempt.Error) ||
+				IsDNSError(attempt.Error))
+	})
+)
+
+//////
+// Built-in policies.
+//////
+
+// IdempotentRetryPolicy restricts `policy` to idempotent methods: `GET`,
+// `HEAD`, `OPTIONS`, `TRACE`, `PUT`, and `DELETE`.
+func IdempotentRetryPolicy(policy RetryPolicy) RetryPolicy {
+	return RetryPolicyFunc(func(attempt *Attempt) bool {
+		return IsIdempotentMethod(attempt.Method) && policy.ShouldRetry(attempt)
+	})
+}
+
+// AnyRetryPolicy retries if any of the `policies` says so.
+func AnyRetryPolicy(policies ...RetryPolicy) RetryPolicy {
+	return RetryPolicyFunc(func(attempt *Attempt) bool {
+		for _, policy := range policies {
+			if policy.ShouldRetry(attempt) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// MaxAttemptsRetryPolicy restricts `policy` to `maxAttempts` attempts, first
+// one included.
+func MaxAttemptsRetryPolicy(maxAttempts int, policy RetryPolicy) RetryPolicy {
+	return RetryPolicyFunc(func(attempt *Attempt) bool {
+		return attempt.Number < maxAttempts && policy.ShouldRetry(attempt)
+	})
+}
+
+//////
+// Helpers.
+//////
+
+// IsIdempotentMethod returns true if `method` is idempotent.
+//
+// SEE: https://www.rfc-editor.org/rfc/rfc9110#section-9.2.2
+func IsIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet,
+		http.MethodHead,
+		http.MethodOptions,
+		http.MethodTrace,
+		http.MethodPut,
+		http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTimeoutError returns true if `err` is a network timeout.
+func IsTimeoutError(err error) bool {
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsConnectionError returns true if `err` is a connection reset, refused, or
+// an unexpected connection close.
+func IsConnectionError(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// IsDNSError returns true if `err` is a DNS failure, other than "host not
+// found".
+func IsDNSError(err error) bool {
+	var dnsErr *net.DNSError
+
+	return errors.As(err, &dnsErr) && !dnsErr.IsNotFound
+}
@@ -0,0 +1,236 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/customerror"
+)
+
+func TestRetryPolicies(t *testing.T) {
+	serverErr := customerror.NewFailedToError("request", customerror.WithStatusCode(http.StatusServiceUnavailable))
+
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt *Attempt
+		want    bool
+	}{
+		{
+			name:    "DefaultRetryPolicy - should retry 5xx",
+			policy:  DefaultRetryPolicy,
+			attempt: &Attempt{Method: http.MethodPost, Number: 1, Error: serverErr},
+			want:    true,
+		},
+		{
+			name:    "NeverRetryPolicy - should not retry",
+			policy:  NeverRetryPolicy,
+			attempt: &Attempt{Method: http.MethodGet, Number: 1, Error: serverErr},
+			want:    false,
+		},
+		{
+			name:    "IdempotentRetryPolicy - should retry GET",
+			policy:  IdempotentRetryPolicy(DefaultRetryPolicy),
+			attempt: &Attempt{Method: http.MethodGet, Number: 1, Error: serverErr},
+			want:    true,
+		},
+		{
+			name:    "IdempotentRetryPolicy - should not retry POST",
+			policy:  IdempotentRetryPolicy(DefaultRetryPolicy),
+			attempt: &Attempt{Method: http.MethodPost, Number: 1, Error: serverErr},
+			want:    false,
+		},
+		{
+			name:    "MaxAttemptsRetryPolicy - should retry",
+			policy:  MaxAttemptsRetryPolicy(2, DefaultRetryPolicy),
+			attempt: &Attempt{Method: http.MethodGet, Number: 1, Error: serverErr},
+			want:    true,
+		},
+		{
+			name:    "MaxAttemptsRetryPolicy - should not retry",
+			policy:  MaxAttemptsRetryPolicy(2, DefaultRetryPolicy),
+			attempt: &Attempt{Method: http.MethodGet, Number: 2, Error: serverErr},
+			want:    false,
+		},
+		{
+			name:   "TransientErrorRetryPolicy - should retry connection reset",
+			policy: TransientErrorRetryPolicy,
+			attempt: &Attempt{Method: http.MethodGet, Number: 1, Error: customerror.NewFailedToError(
+				"send request",
+				customerror.WithError(&url.Error{Op: "Get", URL: "/", Err: syscall.ECONNRESET}),
+			)},
+			want: true,
+		},
+		{
+			name:    "TransientErrorRetryPolicy - should retry DNS temporary failure",
+			policy:  TransientErrorRetryPolicy,
+			attempt: &Attempt{Method: http.MethodGet, Number: 1, Error: &net.DNSError{IsTemporary: true}},
+			want:    true,
+		},
+		{
+			name:    "TransientErrorRetryPolicy - should not retry DNS host not found",
+			policy:  TransientErrorRetryPolicy,
+			attempt: &Attempt{Method: http.MethodGet, Number: 1, Error: &net.DNSError{IsNotFound: true}},
+			want:    false,
+		},
+		{
+			name:    "TransientErrorRetryPolicy - should not retry status codes",
+			policy:  TransientErrorRetryPolicy,
+			attempt: &Attempt{Method: http.MethodGet, Number: 1, Error: serverErr, Response: &http.Response{}},
+			want:    false,
+		},
+		{
+			name:    "AnyRetryPolicy - should retry if any policy does",
+			policy:  AnyRetryPolicy(NeverRetryPolicy, DefaultRetryPolicy),
+			attempt: &Attempt{Method: http.MethodGet, Number: 1, Error: serverErr},
+			want:    true,
+		},
+		{
+			name:    "AnyRetryPolicy - should not retry if no policy does",
+			policy:  AnyRetryPolicy(NeverRetryPolicy, TransientErrorRetryPolicy),
+			attempt: &Attempt{Method: http.MethodGet, Number: 1, Error: errors.New("test")},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.ShouldRetry(tt.attempt))
+		})
+	}
+}
+
+func TestClient_request_retryPolicy(t *testing.T) {
+	tests := []struct {
+		name             string
+		clientName       string
+		clientPolicy     RetryPolicy
+		method           string
+		opts             []Func
+		serverStatusCode int
+		expectedAttempts int32
+	}{
+		{
+			name:             "default policy - should retry",
+			clientName:       "policydefault",
+			method:           http.MethodPost,
+			serverStatusCode: http.StatusServiceUnavailable,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "client policy - should not retry",
+			clientName:       "policyclientnever",
+			clientPolicy:     NeverRetryPolicy,
+			method:           http.MethodGet,
+			serverStatusCode: http.StatusServiceUnavailable,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "per-request policy - should not retry",
+			clientName:       "policyrequestnever",
+			method:           http.MethodGet,
+			opts:             []Func{WithRetryPolicy(NeverRetryPolicy)},
+			serverStatusCode: http.StatusServiceUnavailable,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "idempotent policy - should not retry POST",
+			clientName:       "policyidempotentpost",
+			clientPolicy:     IdempotentRetryPolicy(DefaultRetryPolicy),
+			method:           http.MethodPost,
+			serverStatusCode: http.StatusServiceUnavailable,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "idempotent policy - should retry PUT",
+			clientName:       "policyidempotentput",
+			clientPolicy:     IdempotentRetryPolicy(DefaultRetryPolicy),
+			method:           http.MethodPut,
+			serverStatusCode: http.StatusServiceUnavailable,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "status code policy - should retry 409",
+			clientName:       "policystatuscode",
+			clientPolicy:     HTTPStatusCodeClassifier{StatusCodes: []int{http.StatusConflict}},
+			method:           http.MethodPost,
+			serverStatusCode: http.StatusConflict,
+			expectedAttempts: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+
+			// First attempt fails, the second succeeds.
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) == 1 {
+					w.WriteHeader(tt.serverStatusCode)
+
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			c, err := New(tt.clientName, nil, 0, 100*time.Millisecond, 1)
+			assert.NoError(t, err)
+
+			if tt.clientPolicy != nil {
+				c.RetryPolicy = tt.clientPolicy
+			}
+
+			resp, err := c.request(ctx, tt.method, server.URL, tt.opts...)
+			if tt.expectedAttempts == 1 {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+
+				defer resp.Body.Close()
+			}
+
+			assert.Equal(t, tt.expectedAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+func TestClient_Get_retryPolicy_transientError(t *testing.T) {
+	// Grab a free port, and close it, so connections are refused.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	addr := listener.Addr().String()
+
+	assert.NoError(t, listener.Close())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := New("policytransienterror", nil, 0, 100*time.Millisecond, 2)
+	assert.NoError(t, err)
+
+	var attempts int32
+
+	c.RetryPolicy = RetryPolicyFunc(func(attempt *Attempt) bool {
+		atomic.AddInt32(&attempts, 1)
+
+		return TransientErrorRetryPolicy.ShouldRetry(attempt)
+	})
+
+	_, err = c.Get(ctx, "http://"+addr)
+	assert.Error(t, err)
+	assert.True(t, IsConnectionError(err))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+}
@@ -0,0 +1,296 @@
+package httpclient
+
+import (
+	"bufio"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/httpclient/internal/metrics"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// PrometheusContentType is the content type of the Prometheus text exposition
+// format.
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// promPrefixRegex matches chars not allowed in Prometheus metric names.
+var promPrefixRegex = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// promSeriesKey identifies a series of the request metrics.
+type promSeriesKey struct {
+	method      string
+	statusClass string
+}
+
+// prometheusSink aggregates the request metrics by method, and status class.
+type prometheusSink struct {
+	// buckets of the duration histograms, in seconds.
+	buckets []float64
+
+	mu sync.Mutex
+
+	// durations per method, and status class.
+	durations map[promSeriesKey]*metrics.Histogram
+
+	// reqBytes, and respBytes per method.
+	reqBytes  map[string]int64
+	respBytes map[string]int64
+}
+
+// promFamily is a metric family: its metadata, and samples.
+type promFamily struct {
+	help    string
+	typ     string
+	samples []promSample
+}
+
+// promSample is a sample of a metric family.
+type promSample struct {
+	name   string
+	labels string
+	value  string
+}
+
+//////
+// Methods.
+//////
+
+// add adds a sample to the family.
+func (f *promFamily) add(name string, labels []string, value string) {
+	f.samples = append(f.samples, promSample{
+		name:   name,
+		labels: "{" + strings.Join(labels, ",") + "}",
+		value:  value,
+	})
+}
+
+// AddInFlight implements the `MetricsSink` interface. In-flight requests are
+// rendered from the client gauge.
+func (s *prometheusSink) AddInFlight(labels MetricLabels, delta int64) {}
+
+// AddRequestBytes implements the `MetricsSink` interface.
+func (s *prometheusSink) AddRequestBytes(labels MetricLabels, n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reqBytes[labels.Method] += n
+}
+
+// AddResponseBytes implements the `MetricsSink` interface.
+func (s *prometheusSink) AddResponseBytes(labels MetricLabels, n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.respBytes[labels.Method] += n
+}
+
+// ObserveRequest implements the `MetricsSink` interface.
+func (s *prometheusSink) ObserveRequest(labels MetricLabels, duration time.Duration) {
+	key := promSeriesKey{method: labels.Method, statusClass: labels.StatusClass}
+
+	s.mu.Lock()
+
+	h, ok := s.durations[key]
+	if !ok {
+		h = metrics.NewHistogram(s.buckets)
+
+		s.durations[key] = h
+	}
+
+	s.mu.Unlock()
+
+	h.Observe(duration.Seconds())
+}
+
+// writePrometheus writes the client metrics in the Prometheus text exposition
+// format. Names are prefixed, if set, see `WithPrefix`.
+func (m *clientMetrics) writePrometheus(families map[string]*promFamily) {
+	client := promLabel("client", m.name)
+
+	name := func(name string) string {
+		if m.prefix == "" {
+			return name
+		}
+
+		return promPrefixRegex.ReplaceAllString(m.prefix, "_") + "_" + name
+	}
+
+	for _, counter := range []struct {
+		name  string
+		help  string
+		typ   string
+		value *expvar.Int
+	}{
+		{"httpclient_requests_succeeded_total", "Total succeeded requests.", "counter", m.counterSuccess},
+		{"httpclient_requests_failed_total", "Total failed attempts.", "counter", m.counterFailed},
+		{"httpclient_requests_retried_total", "Total retried attempts.", "counter", m.counterRetried},
+		{"httpclient_requests_hedged_total", "Total hedged attempts.", "counter", m.counterHedged},
+		{"httpclient_requests_rate_limited_total", "Total rate limited attempts.", "counter", m.counterRateLimited},
+		{"httpclient_rate_limited_wait_milliseconds_total", "Total wait, in milliseconds, for the rate limiter.", "counter", m.counterRateLimitedWait},
+		{"httpclient_circuit_breaker_rejected_total", "Total attempts rejected by an open circuit breaker.", "counter", m.counterCircuitRejected},
+		{"httpclient_circuit_breaker_transitions_total", "Total circuit breaker state transitions.", "counter", m.counterCircuitTransitions},
+		{"httpclient_bulkhead_rejected_total", "Total attempts rejected by the bulkhead.", "counter", m.counterBulkheadRejected},
+		{"httpclient_requests_in_flight", "Attempts waiting for, or reading, the response.", "gauge", m.gaugeInFlight},
+		{"httpclient_requests_queued", "Attempts waiting for a bulkhead slot.", "gauge", m.gaugeQueued},
+	} {
+		family(families, name(counter.name), counter.help, counter.typ).add(
+			name(counter.name), []string{client}, strconv.FormatInt(counter.value.Value(), 10),
+		)
+	}
+
+	s := m.prometheus
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := family(families, name("httpclient_requests_total"), "Total attempts, by method, and status class.", "counter")
+	durations := family(families, name("httpclient_request_duration_seconds"), "Attempts duration, until the response headers are received.", "histogram")
+
+	for key, h := range s.durations {
+		labels := []string{client, promLabel("method", key.method), promLabel("status", key.statusClass)}
+
+		bounds, cumulative, count, sum := h.Snapshot()
+
+		requests.add(name("httpclient_requests_total"), labels, strconv.FormatUint(count, 10))
+
+		for i, bound := range bounds {
+			le := promLabel("le", strconv.FormatFloat(bound, 'g', -1, 64))
+
+			durations.add(name("httpclient_request_duration_seconds_bucket"), append(labels[:3:3], le), strconv.FormatUint(cumulative[i], 10))
+		}
+
+		durations.add(name("httpclient_request_duration_seconds_bucket"), append(labels[:3:3], promLabel("le", "+Inf")), strconv.FormatUint(count, 10))
+		durations.add(name("httpclient_request_duration_seconds_sum"), labels, strconv.FormatFloat(sum, 'g', -1, 64))
+		durations.add(name("httpclient_request_duration_seconds_count"), labels, strconv.FormatUint(count, 10))
+	}
+
+	for _, bytes := range []struct {
+		name   string
+		help   string
+		values map[string]int64
+	}{
+		{"httpclient_request_bytes_total", "Total request body bytes, by method.", s.reqBytes},
+		{"httpclient_response_bytes_total", "Total response body bytes read, by method.", s.respBytes},
+	} {
+		f := family(families, name(bytes.name), bytes.help, "counter")
+
+		for method, n := range bytes.values {
+			f.add(name(bytes.name), []string{client, promLabel("method", method)}, strconv.FormatInt(n, 10))
+		}
+	}
+}
+
+//////
+// Exported functionalities.
+//////
+
+// WritePrometheus writes the metrics of every client in the Prometheus text
+// exposition format. Output is sorted, hence stable.
+func WritePrometheus(w io.Writer) error {
+	registry.mu.RLock()
+
+	families := make(map[string]*promFamily)
+
+	for _, m := range registry.metrics {
+		m.writePrometheus(families)
+	}
+
+	registry.mu.RUnlock()
+
+	names := make([]string, 0, len(families))
+
+	for name := range families {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+
+	for _, name := range names {
+		f := families[name]
+
+		// Samples of histograms must be kept in order, per series.
+		sort.SliceStable(f.samples, func(i, j int) bool {
+			return promSeries(f.samples[i]) < promSeries(f.samples[j])
+		})
+
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", name, f.help, name, f.typ)
+
+		for _, sample := range f.samples {
+			fmt.Fprintf(bw, "%s%s %s\n", sample.name, sample.labels, sample.value)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// PrometheusHandler returns an `http.Handler` rendering the metrics of every
+// client in the Prometheus text exposition format, e.g.:
+// `http.Handle("/metrics", httpclient.PrometheusHandler())`.
+func PrometheusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", PrometheusContentType)
+
+		if err := WritePrometheus(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+//////
+// Helpers.
+//////
+
+// family returns the family `name`, creating it if needed.
+func family(families map[string]*promFamily, name, help, typ string) *promFamily {
+	f, ok := families[name]
+	if !ok {
+		f = &promFamily{help: help, typ: typ}
+
+		families[name] = f
+	}
+
+	return f
+}
+
+// promSeries returns the series of `sample`, its labels without `le`.
+func promSeries(sample promSample) string {
+	if i := strings.Index(sample.labels, `,le="`); i != -1 {
+		return sample.labels[:i]
+	}
+
+	return strings.TrimSuffix(sample.labels, "}")
+}
+
+// promLabel formats a label, escaping its value.
+func promLabel(name, value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+
+	return fmt.Sprintf(`%s="%s"`, name, value)
+}
+
+//////
+// Factory.
+//////
+
+// newPrometheusSink creates the Prometheus sink.
+func newPrometheusSink(buckets []time.Duration) *prometheusSink {
+	return &prometheusSink{
+		buckets:   toSeconds(buckets),
+		durations: make(map[promSeriesKey]*metrics.Histogram),
+		reqBytes:  make(map[string]int64),
+		respBytes: make(map[string]int64),
+	}
+}
@@ -0,0 +1,87 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusHandler(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+
+		//nolint:errcheck
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	c, err := Initialize(
+		WithClientName("prometheus"),
+		WithClientLatencyBuckets(time.Minute, time.Hour),
+	)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.Get(ctx, server.URL)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", readBody(t, resp))
+	}
+
+	//nolint:bodyclose
+	_, err = c.Delete(ctx, server.URL)
+	assert.True(t, IsNotFound(err))
+
+	recorder := httptest.NewRecorder()
+
+	PrometheusHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, PrometheusContentType, recorder.Header().Get("Content-Type"))
+
+	output := recorder.Body.String()
+
+	for _, expected := range []string{
+		"# HELP httpclient_requests_total Total attempts, by method, and status class.\n# TYPE httpclient_requests_total counter\n",
+		`httpclient_requests_total{client="prometheus",method="GET",status="2xx"} 2`,
+		`httpclient_requests_total{client="prometheus",method="DELETE",status="4xx"} 1`,
+		"# TYPE httpclient_request_duration_seconds histogram\n",
+		`httpclient_request_duration_seconds_bucket{client="prometheus",method="GET",status="2xx",le="60"} 2` + "\n" +
+			`httpclient_request_duration_seconds_bucket{client="prometheus",method="GET",status="2xx",le="3600"} 2` + "\n" +
+			`httpclient_request_duration_seconds_bucket{client="prometheus",method="GET",status="2xx",le="+Inf"} 2` + "\n" +
+			`httpclient_request_duration_seconds_sum{client="prometheus",method="GET",status="2xx"} `,
+		`httpclient_request_duration_seconds_count{client="prometheus",method="GET",status="2xx"} 2`,
+		`httpclient_requests_succeeded_total{client="prometheus"} 2`,
+		`httpclient_requests_in_flight{client="prometheus"} 0`,
+		`httpclient_response_bytes_total{client="prometheus",method="GET"} 4`,
+	} {
+		assert.Contains(t, output, expected)
+	}
+
+	// Stable.
+	recorder = httptest.NewRecorder()
+
+	PrometheusHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, output, recorder.Body.String())
+
+	// Each family is declared once.
+	assert.Equal(t, 1, strings.Count(output, "# TYPE httpclient_requests_total "))
+}
+
+func TestPromLabel(t *testing.T) {
+	assert.Equal(t, `client="a\"b\\c\nd"`, promLabel("client", "a\"b\\c\nd"))
+}
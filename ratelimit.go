@@ -0,0 +1,228 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/customerror"
+	"github.com/thalesfsp/sypl"
+	"github.com/thalesfsp/sypl/level"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// RateLimitScope defines what shares a token bucket.
+type RateLimitScope string
+
+const (
+	// RateLimitPerClient shares a bucket across all requests of the client.
+	RateLimitPerClient RateLimitScope = "client"
+
+	// RateLimitPerHost has a bucket per host.
+	RateLimitPerHost RateLimitScope = "host"
+
+	// RateLimitPerRoute has a bucket per method, and URL template, before path
+	// params are filled, e.g.: `GET /users/{id}`. Requests without path params,
+	// see `WithPathParam`, have a bucket per method, and host.
+	RateLimitPerRoute RateLimitScope = "route"
+)
+
+// RateLimitConfig configures the client-side, token bucket, rate limiter.
+type RateLimitConfig struct {
+	// Burst is the max number of requests sent at once. Defaults to 1.
+	Burst int
+
+	// RequestsPerSecond is the rate tokens are refilled at.
+	RequestsPerSecond float64
+
+	// Scope defines what shares a bucket. Defaults to `RateLimitPerClient`.
+	Scope RateLimitScope
+}
+
+// tokenBucket is a token bucket. Tokens go negative when reserved ahead of
+// time, which is how waiters queue up.
+type tokenBucket struct {
+	burst  float64
+	rate   float64
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter holds the token buckets, per scope key.
+type rateLimiter struct {
+	config RateLimitConfig
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+//////
+// Methods.
+//////
+
+// reserve takes a token, returning how long to wait for it.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+
+		b.last = now
+	}
+
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// release gives back a reserved, but not used, token.
+func (b *tokenBucket) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens++
+
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// key returns the bucket key of a request.
+func (l *rateLimiter) key(method, route string, req *http.Request) string {
+	switch l.config.Scope {
+	case RateLimitPerHost:
+		return req.URL.Host
+	case RateLimitPerRoute:
+		if route == "" {
+			return method + " " + req.URL.Host
+		}
+
+		route, _, _ = strings.Cut(route, "?")
+
+		return method + " " + route
+	default:
+		return ""
+	}
+}
+
+// bucket returns the bucket of `key`, creating it if needed.
+func (l *rateLimiter) bucket(key string) *tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{
+			burst:  float64(l.config.Burst),
+			rate:   l.config.RequestsPerSecond,
+			tokens: float64(l.config.Burst),
+			last:   time.Now(),
+		}
+
+		l.buckets[key] = b
+	}
+
+	return b
+}
+
+// Wait waits for a token, respecting `ctx`. It returns how long it waited.
+// If the wait would exceed the context deadline, it fails right away.
+func (l *rateLimiter) Wait(ctx context.Context, key string) (time.Duration, error) {
+	b := l.bucket(key)
+
+	wait := b.reserve(time.Now())
+	if wait == 0 {
+		return 0, nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+		b.release()
+
+		return 0, fmt.Errorf("wait %s for rate limiter, it exceeds the context deadline: %w", wait, context.DeadlineExceeded)
+	}
+
+	timer := time.NewTimer(wait)
+
+	select {
+	case <-timer.C:
+		return wait, nil
+	case <-ctx.Done():
+		timer.Stop()
+
+		b.release()
+
+		return 0, ctx.Err()
+	}
+}
+
+// waitRateLimit waits for the rate limiter, if any, recording the wait in
+// logs, and metrics.
+func (c *Client) waitRateLimit(ctx context.Context, method, route string, req *http.Request) error {
+	if c.rateLimiter == nil {
+		return nil
+	}
+
+	wait, err := c.rateLimiter.Wait(ctx, c.rateLimiter.key(method, route, req))
+	if err != nil {
+		return err
+	}
+
+	if wait > 0 {
+		c.counterRateLimited.Add(1)
+		c.counterRateLimitedWait.Add(wait.Milliseconds())
+
+		c.GetLogger().PrintlnWithOptions(
+			level.Debug,
+			fmt.Sprintf("rate limited, waited %s", wait),
+			sypl.WithField("method", method),
+			sypl.WithField("url", c.Redactor.URL(req.URL.String())),
+			sypl.WithTags("request", "ratelimited"),
+		)
+	}
+
+	return nil
+}
+
+//////
+// Factory.
+//////
+
+// newRateLimiter creates a rate limiter based on `config`.
+func newRateLimiter(config RateLimitConfig) (*rateLimiter, error) {
+	if config.RequestsPerSecond <= 0 {
+		return nil, customerror.NewInvalidError("rate limit, requests per second must be greater than zero")
+	}
+
+	if config.Burst <= 0 {
+		config.Burst = 1
+	}
+
+	switch config.Scope {
+	case "":
+		config.Scope = RateLimitPerClient
+	case RateLimitPerClient, RateLimitPerHost, RateLimitPerRoute:
+	default:
+		return nil, customerror.NewInvalidError("rate limit scope " + string(config.Scope))
+	}
+
+	return &rateLimiter{
+		buckets: make(map[string]*tokenBucket),
+		config:  config,
+	}, nil
+}
@@ -0,0 +1,212 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket_reserve(t *testing.T) {
+	now := time.Now()
+
+	b := &tokenBucket{burst: 2, rate: 10, tokens: 2, last: now}
+
+	assert.Equal(t, time.Duration(0), b.reserve(now))
+	assert.Equal(t, time.Duration(0), b.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, b.reserve(now))
+	assert.Equal(t, 200*time.Millisecond, b.reserve(now))
+
+	// Refilled, capped at burst.
+	assert.Equal(t, time.Duration(0), b.reserve(now.Add(time.Hour)))
+	assert.Equal(t, time.Duration(0), b.reserve(now.Add(time.Hour)))
+	assert.Equal(t, 100*time.Millisecond, b.reserve(now.Add(time.Hour)))
+}
+
+func TestClient_Get_rateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	otherServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer otherServer.Close()
+
+	tests := []struct {
+		name            string
+		clientName      string
+		config          RateLimitConfig
+		urls            []string
+		expectedMinWait time.Duration
+		expectedMaxWait time.Duration
+	}{
+		{
+			name:            "per client",
+			clientName:      "ratelimitclient",
+			config:          RateLimitConfig{RequestsPerSecond: 10},
+			urls:            []string{server.URL, otherServer.URL, server.URL, otherServer.URL},
+			expectedMinWait: 300 * time.Millisecond,
+			expectedMaxWait: 1 * time.Second,
+		},
+		{
+			name:            "per client - burst",
+			clientName:      "ratelimitburst",
+			config:          RateLimitConfig{RequestsPerSecond: 1, Burst: 4},
+			urls:            []string{server.URL, server.URL, server.URL, server.URL},
+			expectedMaxWait: 500 * time.Millisecond,
+		},
+		{
+			name:            "per host",
+			clientName:      "ratelimithost",
+			config:          RateLimitConfig{RequestsPerSecond: 10, Scope: RateLimitPerHost},
+			urls:            []string{server.URL, otherServer.URL, server.URL, otherServer.URL},
+			expectedMinWait: 100 * time.Millisecond,
+			expectedMaxWait: 300 * time.Millisecond,
+		},
+		{
+			name:            "per route",
+			clientName:      "ratelimitroute",
+			config:          RateLimitConfig{RequestsPerSecond: 10, Scope: RateLimitPerRoute},
+			urls:            []string{server.URL + "/a/{id}", server.URL + "/b/{id}", server.URL + "/a/{id}", server.URL + "/b/{id}"},
+			expectedMinWait: 100 * time.Millisecond,
+			expectedMaxWait: 300 * time.Millisecond,
+		},
+		{
+			name:            "per route - without path params, per host",
+			clientName:      "ratelimitroutehost",
+			config:          RateLimitConfig{RequestsPerSecond: 10, Scope: RateLimitPerRoute},
+			urls:            []string{server.URL + "/a/1", server.URL + "/b/2", server.URL + "/a/3", server.URL + "/b/4"},
+			expectedMinWait: 300 * time.Millisecond,
+			expectedMaxWait: 1 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			c, err := Initialize(WithClientName(tt.clientName), WithClientRateLimit(tt.config))
+			assert.NoError(t, err)
+
+			now := time.Now()
+
+			for i, url := range tt.urls {
+				resp, err := c.Get(ctx, url, WithPathParam("id", strconv.Itoa(i)))
+				assert.NoError(t, err)
+				assert.NoError(t, resp.Body.Close())
+			}
+
+			elapsed := time.Since(now)
+
+			assert.GreaterOrEqual(t, elapsed, tt.expectedMinWait)
+			assert.Less(t, elapsed, tt.expectedMaxWait)
+			assert.Equal(t, tt.expectedMinWait > 0, c.counterRateLimited.Value() > 0)
+			assert.Equal(t, tt.expectedMinWait > 0, c.counterRateLimitedWait.Value() > 0)
+		})
+	}
+}
+
+func TestClient_Get_rateLimitDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := Initialize(
+		WithClientName("ratelimitdeadline"),
+		WithClientRateLimit(RateLimitConfig{RequestsPerSecond: 1}),
+	)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	resp, err := c.Get(ctx, server.URL)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	now := time.Now()
+
+	//nolint:bodyclose
+	_, err = c.Get(ctx, server.URL)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// Fails right away, instead of waiting.
+	assert.Less(t, time.Since(now), 100*time.Millisecond)
+}
+
+func TestClient_Get_rateLimitCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := Initialize(
+		WithClientName("ratelimitcanceled"),
+		WithClientRateLimit(RateLimitConfig{RequestsPerSecond: 1}),
+	)
+	assert.NoError(t, err)
+
+	resp, err := c.Get(context.Background(), server.URL)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	//nolint:bodyclose
+	_, err = c.Get(ctx, server.URL)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	// A canceled wait isn't a server failure, so it isn't retried.
+	assert.Equal(t, int64(0), c.counterRetried.Value())
+}
+
+func TestClient_ParallelGet_rateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Initialize(
+		WithClientName("ratelimitparallelget"),
+		WithClientRateLimit(RateLimitConfig{RequestsPerSecond: 20}),
+	)
+	assert.NoError(t, err)
+
+	now := time.Now()
+
+	responses, errs := c.ParallelGet(ctx, nil, server.URL, server.URL, server.URL, server.URL, server.URL)
+	assert.Empty(t, errs)
+
+	for _, resp := range responses {
+		assert.NoError(t, resp.Body.Close())
+	}
+
+	assert.GreaterOrEqual(t, time.Since(now), 200*time.Millisecond)
+}
+
+func TestInitialize_invalidRateLimit(t *testing.T) {
+	_, err := Initialize(
+		WithClientName("ratelimitinvalid"),
+		WithClientRateLimit(RateLimitConfig{}),
+	)
+	assert.Error(t, err)
+
+	_, err = Initialize(
+		WithClientName("ratelimitinvalidscope"),
+		WithClientRateLimit(RateLimitConfig{RequestsPerSecond: 1, Scope: "invalid"}),
+	)
+	assert.Error(t, err)
+}
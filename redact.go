@@ -0,0 +1,314 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// DefaultRedactedValue replaces redacted values.
+const DefaultRedactedValue = "[REDACTED]"
+
+var (
+	// DefaultRedactedHeaders are the headers redacted by default.
+	DefaultRedactedHeaders = []string{
+		"Authorization",
+		"Cookie",
+		"Proxy-Authorization",
+		"Set-Cookie",
+		"X-Api-Key",
+	}
+
+	// DefaultRedactedQueryParams are the query params, and form fields
+	// redacted by default.
+	DefaultRedactedQueryParams = []string{
+		"access_token",
+		"api_key",
+		"apikey",
+		"client_secret",
+		"password",
+		"token",
+	}
+
+	// DefaultRedactedJSONFields are the JSON fields redacted by default.
+	DefaultRedactedJSONFields = []string{
+		"access_token",
+		"client_secret",
+		"password",
+		"refresh_token",
+		"secret",
+		"token",
+	}
+)
+
+// Redactor redacts secrets from headers, URLs, and bodies before they are
+// logged. Names are case-insensitive.
+//
+// NOTE: A nil `Redactor` redacts nothing.
+type Redactor struct {
+	// Headers to be redacted.
+	Headers []string
+
+	// JSONFields to be redacted. A name (e.g.: `password`) matches the field at
+	// any depth, a path (e.g.: `user.password`) matches from the root. Arrays
+	// are traversed.
+	JSONFields []string
+
+	// QueryParams to be redacted. They also apply to form-encoded bodies.
+	QueryParams []string
+
+	// Replacement of redacted values. Defaults to `DefaultRedactedValue`.
+	Replacement string
+}
+
+//////
+// Methods.
+//////
+
+// replacement returns the replacement of redacted values.
+func (r *Redactor) replacement() string {
+	if r.Replacement == "" {
+		return DefaultRedactedValue
+	}
+
+	return r.Replacement
+}
+
+// isRedactedHeader returns true if the `name` header should be redacted.
+func (r *Redactor) isRedactedHeader(name string) bool {
+	for _, h := range r.Headers {
+		if strings.EqualFold(h, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isRedactedQueryParam returns true if the `name` query param, or form field
+// should be redacted.
+func (r *Redactor) isRedactedQueryParam(name string) bool {
+	for _, q := range r.QueryParams {
+		if strings.EqualFold(q, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isRedactedJSONField returns true if the `key` field, at `path`, should be
+// redacted.
+func (r *Redactor) isRedactedJSONField(key, path string) bool {
+	for _, f := range r.JSONFields {
+		if strings.Contains(f, ".") {
+			if strings.EqualFold(f, path) {
+				return true
+			}
+
+			continue
+		}
+
+		if strings.EqualFold(f, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Header returns a redacted copy of `h`.
+func (r *Redactor) Header(h http.Header) http.Header {
+	if r == nil || h == nil {
+		return h
+	}
+
+	redacted := h.Clone()
+
+	for k, v := range redacted {
+		if !r.isRedactedHeader(k) {
+			continue
+		}
+
+		for i := range v {
+			v[i] = r.replacement()
+		}
+	}
+
+	return redacted
+}
+
+// HeaderMap returns a redacted copy of `h`.
+func (r *Redactor) HeaderMap(h map[string]string) map[string]string {
+	if r == nil || h == nil {
+		return h
+	}
+
+	redacted := make(map[string]string, len(h))
+
+	for k, v := range h {
+		if r.isRedactedHeader(k) {
+			v = r.replacement()
+		}
+
+		redacted[k] = v
+	}
+
+	return redacted
+}
+
+// Query returns a redacted `rawQuery`, e.g.: `a=1&token=x` -> `a=1&token=
+// [REDACTED]`. Order, and encoding of non-redacted params are preserved.
+func (r *Redactor) Query(rawQuery string) string {
+	if r == nil || rawQuery == "" {
+		return rawQuery
+	}
+
+	params := strings.Split(rawQuery, "&")
+
+	for i, param := range params {
+		k, _, _ := strings.Cut(param, "=")
+
+		name, err := url.QueryUnescape(k)
+		if err != nil {
+			name = k
+		}
+
+		if r.isRedactedQueryParam(name) || r.isRedactedJSONField(name, name) {
+			params[i] = k + "=" + r.replacement()
+		}
+	}
+
+	return strings.Join(params, "&")
+}
+
+// URL returns a redacted `rawURL`: query params, and user info password.
+func (r *Redactor) URL(rawURL string) string {
+	if r == nil || rawURL == "" {
+		return rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		// Can't parse it, play safe.
+		base, _, _ := strings.Cut(rawURL, "?")
+
+		return base
+	}
+
+	u.RawQuery = r.Query(u.RawQuery)
+
+	return u.Redacted()
+}
+
+// Body returns a redacted, printable, representation of `body`. JSON bodies
+// are redacted according to `JSONFields`, form-encoded ones according to
+// `QueryParams`. `io.Reader`s aren't read.
+func (r *Redactor) Body(body any) string {
+	switch b := body.(type) {
+	case nil:
+		return ""
+	case string:
+		return r.rawBody([]byte(b))
+	case []byte:
+		return r.rawBody(b)
+	case url.Values:
+		return r.Query(b.Encode())
+	case io.Reader:
+		return fmt.Sprintf("%T", b)
+	default:
+		bodyBytes, err := json.Marshal(b)
+		if err != nil {
+			return fmt.Sprintf("%T", b)
+		}
+
+		return r.rawBody(bodyBytes)
+	}
+}
+
+// rawBody redacts a raw body, either JSON, or form-encoded.
+func (r *Redactor) rawBody(b []byte) string {
+	if r == nil {
+		return string(b)
+	}
+
+	var v any
+
+	if err := json.Unmarshal(b, &v); err != nil {
+		return r.Query(string(b))
+	}
+
+	redacted, err := json.Marshal(r.json(v, ""))
+	if err != nil {
+		return string(b)
+	}
+
+	return string(redacted)
+}
+
+// json redacts, recursively, the JSON value `v` at `path`.
+func (r *Redactor) json(v any, path string) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			p := k
+
+			if path != "" {
+				p = path + "." + k
+			}
+
+			if r.isRedactedJSONField(k, p) {
+				t[k] = r.replacement()
+
+				continue
+			}
+
+			t[k] = r.json(val, p)
+		}
+	case []any:
+		for i := range t {
+			t[i] = r.json(t[i], path)
+		}
+	}
+
+	return v
+}
+
+// Message returns `msg` with occurrences of `rawURLs` redacted, e.g.: in
+// transport errors.
+func (r *Redactor) Message(msg string, rawURLs ...string) string {
+	if r == nil {
+		return msg
+	}
+
+	for _, rawURL := range rawURLs {
+		if rawURL == "" {
+			continue
+		}
+
+		msg = strings.ReplaceAll(msg, rawURL, r.URL(rawURL))
+	}
+
+	return msg
+}
+
+//////
+// Factory.
+//////
+
+// NewRedactor creates a `Redactor` with the default deny-lists, plus the
+// provided ones.
+func NewRedactor(headers, queryParams, jsonFields []string) *Redactor {
+	return &Redactor{
+		Headers:     append(append([]string{}, DefaultRedactedHeaders...), headers...),
+		JSONFields:  append(append([]string{}, DefaultRedactedJSONFields...), jsonFields...),
+		QueryParams: append(append([]string{}, DefaultRedactedQueryParams...), queryParams...),
+	}
+}
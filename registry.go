@@ -0,0 +1,215 @@
+package httpclient
+
+import (
+	"expvar"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/status"
+
+	"github.com/thalesfsp/httpclient/internal/metrics"
+	"github.com/thalesfsp/httpclient/internal/shared"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// registry holds the metrics of every client, by name, and prefix.
+var registry = struct {
+	mu      sync.RWMutex
+	metrics map[string]*clientMetrics
+}{metrics: make(map[string]*clientMetrics)}
+
+// MetricsSnapshot is a point-in-time copy of the client metrics.
+type MetricsSnapshot struct {
+	// BulkheadRejected is the number of attempts rejected by the bulkhead.
+	BulkheadRejected int64 `json:"bulkheadRejected"`
+
+	// CircuitBreakerStates is the circuit breaker state per host.
+	CircuitBreakerStates map[string]string `json:"circuitBreakerStates"`
+
+	// CircuitRejected is the number of attempts rejected by an open circuit
+	// breaker.
+	CircuitRejected int64 `json:"circuitRejected"`
+
+	// CircuitTransitions is the number of circuit breaker state transitions.
+	CircuitTransitions int64 `json:"circuitTransitions"`
+
+	// Failed is the number of failed attempts.
+	Failed int64 `json:"failed"`
+
+	// Hedged is the number of hedged attempts.
+	Hedged int64 `json:"hedged"`
+
+	// InFlight is the number of attempts waiting for, or reading, the response.
+	InFlight int64 `json:"inFlight"`
+
+	// Queued is the number of attempts waiting for a bulkhead slot.
+	Queued int64 `json:"queued"`
+
+	// RateLimited is the number of rate limited attempts.
+	RateLimited int64 `json:"rateLimited"`
+
+	// RateLimitedWait is the total wait for the rate limiter.
+	RateLimitedWait time.Duration `json:"rateLimitedWait"`
+
+	// Retried is the number of retried attempts.
+	Retried int64 `json:"retried"`
+
+	// Succeeded is the number of succeeded requests.
+	Succeeded int64 `json:"succeeded"`
+}
+
+// clientMetrics are the metrics of a client. Clients with the same name, and
+// prefix share them.
+type clientMetrics struct {
+	// name of the client, and prefix of the metric names.
+	name   string
+	prefix string
+
+	// Request's metrics.
+	counterFailed  *expvar.Int
+	counterHedged  *expvar.Int
+	counterRetried *expvar.Int
+	counterSuccess *expvar.Int
+
+	// Rate limiter's metrics: throttled requests, and total wait in ms.
+	counterRateLimited     *expvar.Int
+	counterRateLimitedWait *expvar.Int
+
+	// Circuit breaker's metrics: state transitions, rejected requests, and
+	// state per host.
+	counterCircuitRejected    *expvar.Int
+	counterCircuitTransitions *expvar.Int
+	circuitBreakerState       *expvar.Map
+
+	// Bulkhead's metrics: in-flight, and queued requests gauges, and rejected
+	// requests.
+	counterBulkheadRejected *expvar.Int
+	gaugeInFlight           *expvar.Int
+	gaugeQueued             *expvar.Int
+
+	// expvar exposes the per-attempt metrics.
+	expvar *expvarSink
+
+	// prometheus aggregates the metrics rendered by `PrometheusHandler`.
+	prometheus *prometheusSink
+}
+
+//////
+// Methods.
+//////
+
+// snapshot returns a point-in-time copy of the metrics.
+func (m *clientMetrics) snapshot() MetricsSnapshot {
+	states := make(map[string]string)
+
+	m.circuitBreakerState.Do(func(kv expvar.KeyValue) {
+		if s, ok := kv.Value.(interface{ Value() string }); ok {
+			states[kv.Key] = s.Value()
+		}
+	})
+
+	return MetricsSnapshot{
+		BulkheadRejected:     m.counterBulkheadRejected.Value(),
+		CircuitBreakerStates: states,
+		CircuitRejected:      m.counterCircuitRejected.Value(),
+		CircuitTransitions:   m.counterCircuitTransitions.Value(),
+		Failed:               m.counterFailed.Value(),
+		Hedged:               m.counterHedged.Value(),
+		InFlight:             m.gaugeInFlight.Value(),
+		Queued:               m.gaugeQueued.Value(),
+		RateLimited:          m.counterRateLimited.Value(),
+		RateLimitedWait:      time.Duration(m.counterRateLimitedWait.Value()) * time.Millisecond,
+		Retried:              m.counterRetried.Value(),
+		Succeeded:            m.counterSuccess.Value(),
+	}
+}
+
+// Metrics returns a point-in-time copy of the client metrics.
+//
+// NOTE: Clients with the same name, and prefix share their metrics.
+func (c *Client) Metrics() MetricsSnapshot {
+	return c.snapshot()
+}
+
+//////
+// Exported functionalities.
+//////
+
+// GetMetrics returns a point-in-time copy of the metrics of the client
+// `name`, with the prefix `prefix`, if any, see `WithPrefix`.
+func GetMetrics(prefix, name string) (MetricsSnapshot, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	m, ok := registry.metrics[metricName(prefix, name)]
+	if !ok {
+		return MetricsSnapshot{}, false
+	}
+
+	return m.snapshot(), true
+}
+
+//////
+// Helpers.
+//////
+
+// metricName returns the stable name of a metric, e.g.:
+// `{prefix}.httpclient.{name}.failed.counter`.
+func metricName(prefix, name string, parts ...string) string {
+	names := make([]string, 0, len(parts)+3)
+
+	if prefix != "" {
+		names = append(names, prefix)
+	}
+
+	names = append(names, shared.PackageName, name)
+	names = append(names, parts...)
+
+	return strings.Join(names, ".")
+}
+
+// registerMetrics returns the metrics of the client `name`, with the prefix
+// `prefix`, creating them if needed. Registration is idempotent, the latency
+// `buckets` of the first one are used.
+func registerMetrics(prefix, name string, buckets []time.Duration) *clientMetrics {
+	key := metricName(prefix, name)
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if m, ok := registry.metrics[key]; ok {
+		return m
+	}
+
+	m := &clientMetrics{
+		name:   name,
+		prefix: prefix,
+
+		counterFailed:  metrics.NewInt(metricName(prefix, name, status.Failed.String(), DefaultMetricCounterLabel)),
+		counterHedged:  metrics.NewInt(metricName(prefix, name, "hedged", DefaultMetricCounterLabel)),
+		counterRetried: metrics.NewInt(metricName(prefix, name, status.Retried.String(), DefaultMetricCounterLabel)),
+		counterSuccess: metrics.NewInt(metricName(prefix, name, status.Succeeded.String(), DefaultMetricCounterLabel)),
+
+		counterRateLimited:     metrics.NewInt(metricName(prefix, name, "ratelimited", DefaultMetricCounterLabel)),
+		counterRateLimitedWait: metrics.NewInt(metricName(prefix, name, "ratelimited.wait.ms", DefaultMetricCounterLabel)),
+
+		counterCircuitRejected:    metrics.NewInt(metricName(prefix, name, "circuitbreaker.rejected", DefaultMetricCounterLabel)),
+		counterCircuitTransitions: metrics.NewInt(metricName(prefix, name, "circuitbreaker.transitions", DefaultMetricCounterLabel)),
+		circuitBreakerState:       metrics.NewMap(metricName(prefix, name, "circuitbreaker.state")),
+
+		counterBulkheadRejected: metrics.NewInt(metricName(prefix, name, "bulkhead.rejected", DefaultMetricCounterLabel)),
+		gaugeInFlight:           metrics.NewInt(metricName(prefix, name, "inflight.gauge")),
+		gaugeQueued:             metrics.NewInt(metricName(prefix, name, "queued.gauge")),
+
+		expvar:     newExpvarSink(prefix, name, buckets),
+		prometheus: newPrometheusSink(buckets),
+	}
+
+	registry.metrics[key] = m
+
+	return m
+}
@@ -0,0 +1,87 @@
+package httpclient
+
+import (
+	"context"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitialize_sameName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Used to panic, re-registering the same expvar name.
+	c1, err := Initialize(WithClientName("registrysamename"))
+	assert.NoError(t, err)
+
+	c2, err := Initialize(WithClientName("registrysamename"))
+	assert.NoError(t, err)
+
+	resp, err := c1.Get(ctx, server.URL)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	resp, err = c2.Get(ctx, server.URL)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	// Shared, and stable names.
+	assert.Equal(t, int64(2), c1.Metrics().Succeeded)
+	assert.Equal(t, c1.Metrics(), c2.Metrics())
+	assert.Equal(t, "2", expvar.Get("httpclient.registrysamename.succeeded.counter").String())
+
+	snapshot, ok := GetMetrics("", "registrysamename")
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), snapshot.Succeeded)
+	assert.Equal(t, int64(0), snapshot.InFlight)
+
+	_, ok = GetMetrics("", "registrynotfound")
+	assert.False(t, ok)
+}
+
+func TestInitialize_metricsPrefix(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c, err := Initialize(
+		WithClientName("registryprefix"),
+		WithClientRetrier(100*time.Millisecond, 1),
+		WithPrefix("acme-corp"),
+	)
+	assert.NoError(t, err)
+
+	//nolint:bodyclose
+	_, err = c.Get(context.Background(), server.URL)
+	assert.True(t, IsServerError(err))
+
+	// The env var isn't mutated.
+	assert.Empty(t, os.Getenv("HTTPCLIENT_METRICS_PREFIX"))
+
+	assert.NotNil(t, expvar.Get("acme-corp.httpclient.registryprefix.retried.counter"))
+	assert.Nil(t, expvar.Get("httpclient.registryprefix.retried.counter"))
+
+	snapshot, ok := GetMetrics("acme-corp", "registryprefix")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), snapshot.Retried)
+	assert.Equal(t, c.Metrics(), snapshot)
+
+	recorder := httptest.NewRecorder()
+
+	PrometheusHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Contains(t, recorder.Body.String(), `acme_corp_httpclient_requests_retried_total{client="registryprefix"} 1`)
+	assert.Contains(t, recorder.Body.String(), `acme_corp_httpclient_requests_total{client="registryprefix",method="GET",status="5xx"} 2`)
+}
@@ -0,0 +1,257 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thalesfsp/status"
+	"github.com/thalesfsp/sypl"
+	"github.com/thalesfsp/sypl/level"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// retryConfig is the resolved retry settings for a request.
+type retryConfig struct {
+	backoff    Backoff
+	maxBudget  time.Duration
+	maxRetries int
+	maxWait    time.Duration
+	policy     RetryPolicy
+}
+
+// unixTimestampThreshold differentiates, for `X-RateLimit-Reset`, a Unix
+// timestamp (e.g.: GitHub) from an amount of seconds (e.g.: Twitter).
+const unixTimestampThreshold = 1_000_000_000
+
+//////
+// Helpers.
+//////
+
+// retryAfter returns how long the server asked to wait before retrying. It
+// looks, in order, into the `Retry-After` (seconds, or HTTP-date),
+// `RateLimit-Reset` (seconds), and `X-RateLimit-Reset` (seconds, or Unix
+// timestamp) headers.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	if v := strings.TrimSpace(resp.Header.Get("Retry-After")); v != "" {
+		if seconds, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return secondsToDuration(seconds), true
+		}
+
+		if date, err := http.ParseTime(v); err == nil {
+			return nonNegative(date.Sub(now)), true
+		}
+	}
+
+	if v := strings.TrimSpace(resp.Header.Get("RateLimit-Reset")); v != "" {
+		if seconds, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return secondsToDuration(seconds), true
+		}
+	}
+
+	if v := strings.TrimSpace(resp.Header.Get("X-RateLimit-Reset")); v != "" {
+		if seconds, err := strconv.ParseInt(v, 10, 64); err == nil {
+			if seconds >= unixTimestampThreshold {
+				return nonNegative(time.Unix(seconds, 0).Sub(now)), true
+			}
+
+			return secondsToDuration(seconds), true
+		}
+	}
+
+	return 0, false
+}
+
+// secondsToDuration converts seconds to duration, never negative.
+func secondsToDuration(seconds int64) time.Duration {
+	return nonNegative(time.Duration(seconds) * time.Second)
+}
+
+// nonNegative returns `d`, or zero if it's negative.
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+
+	return d
+}
+
+//////
+// Methods.
+//////
+
+// newRetryConfig resolves the retry settings for a request. Per-request
+// settings take precedence over the client's ones.
+func (c *Client) newRetryConfig(o *Options) *retryConfig {
+	cfg := &retryConfig{
+		backoff:    c.Backoff,
+		maxBudget:  c.RetrierMaxBudget,
+		maxRetries: c.RetrierBackoffTimes,
+		maxWait:    c.RetrierMaxWait,
+		policy:     c.RetryPolicy,
+	}
+
+	if o.Backoff != nil {
+		cfg.backoff = o.Backoff
+	}
+
+	if o.RetrierMaxBudget > 0 {
+		cfg.maxBudget = o.RetrierMaxBudget
+	}
+
+	if o.RetrierMaxWait > 0 {
+		cfg.maxWait = o.RetrierMaxWait
+	}
+
+	if o.RetryPolicy != nil {
+		cfg.policy = o.RetryPolicy
+	}
+
+	if cfg.backoff == nil {
+		cfg.backoff = ExponentialBackoff(c.RetrierBackoffDuration)
+	}
+
+	if cfg.policy == nil {
+		cfg.policy = DefaultRetryPolicy
+	}
+
+	return cfg
+}
+
+// retry runs `work` until it succeeds, fails, or retries are exhausted, based
+// on the retry policy, and the backoff. Backoff is extended to what the server
+// asked for (see `retryAfter`), capped at the max wait.
+//
+// NOTE: It gives up (returning the last error) instead of retrying too early,
+// if the server asks to wait more than the max wait, or if the wait would
+// exceed the retry budget, or the context deadline.
+//
+//nolint:cyclop
+func (c *Client) retry(
+	ctx context.Context,
+	method string,
+	url string,
+	cfg *retryConfig,
+	work func() (*http.Response, error),
+) (*http.Response, error) {
+	var previous, waited time.Duration
+
+	// Retry history, see `HTTPError`.
+	var history []RetryRecord
+
+	for retries := 0; ; retries++ {
+		resp, err := work()
+
+		var httpErr *HTTPError
+
+		if errors.As(err, &httpErr) {
+			httpErr.Attempts = retries + 1
+			httpErr.Retries = history
+		}
+
+		// Nothing to retry, the request is done, the body can't be sent again,
+		// the host is down, or too many requests are in-flight.
+		//
+		// NOTE: Waits which can't complete, e.g.: for the rate limiter, or the
+		// bulkhead, fail with plain context errors, not `CustomError`s, so
+		// `HTTPStatusCodeClassifier` doesn't take them for server failures.
+		if err == nil ||
+			ctx.Err() != nil ||
+			errors.Is(err, ErrReqBodyNotReplayable) ||
+			errors.Is(err, ErrCircuitOpen) ||
+			errors.Is(err, ErrBulkheadTimeout) ||
+			retries >= cfg.maxRetries {
+			return resp, err
+		}
+
+		if !cfg.policy.ShouldRetry(&Attempt{
+			Error:    err,
+			Method:   method,
+			Number:   retries + 1,
+			Response: resp,
+			URL:      url,
+		}) {
+			return resp, err
+		}
+
+		wait := cfg.backoff.Delay(retries+1, previous)
+
+		if serverWait, ok := retryAfter(resp, time.Now()); ok {
+			if cfg.maxWait > 0 && serverWait > cfg.maxWait {
+				c.GetLogger().PrintlnWithOptions(
+					level.Debug,
+					fmt.Sprintf("server asked to wait %s, more than the max %s, not retrying", serverWait, cfg.maxWait),
+					sypl.WithTags("request", status.Retried.String()),
+				)
+
+				return resp, err
+			}
+
+			if serverWait > wait {
+				wait = serverWait
+			}
+		}
+
+		if cfg.maxWait > 0 && wait > cfg.maxWait {
+			wait = cfg.maxWait
+		}
+
+		if cfg.maxBudget > 0 && waited+wait > cfg.maxBudget {
+			c.GetLogger().PrintlnWithOptions(
+				level.Debug,
+				fmt.Sprintf("waiting %s to retry would exceed the retry budget %s, not retrying", wait, cfg.maxBudget),
+				sypl.WithTags("request", status.Retried.String()),
+			)
+
+			return resp, err
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			c.GetLogger().PrintlnWithOptions(
+				level.Debug,
+				fmt.Sprintf("waiting %s to retry would exceed the context deadline, not retrying", wait),
+				sypl.WithTags("request", status.Retried.String()),
+			)
+
+			return resp, err
+		}
+
+		c.counterRetried.Add(1)
+
+		record := RetryRecord{
+			Attempt: retries + 1,
+			Error:   err,
+			Wait:    wait,
+		}
+
+		if resp != nil {
+			record.StatusCode = resp.StatusCode
+		}
+
+		history = append(history, record)
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, ctx.Err()
+		}
+
+		previous = wait
+		waited += wait
+	}
+}
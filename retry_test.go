@@ -0,0 +1,182 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2023, time.February, 8, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    time.Duration
+		wantOK  bool
+	}{
+		{
+			name:    "no headers",
+			headers: map[string]string{},
+		},
+		{
+			name:    "Retry-After - seconds",
+			headers: map[string]string{"Retry-After": "3"},
+			want:    3 * time.Second,
+			wantOK:  true,
+		},
+		{
+			name:    "Retry-After - HTTP-date",
+			headers: map[string]string{"Retry-After": now.Add(5 * time.Second).Format(http.TimeFormat)},
+			want:    5 * time.Second,
+			wantOK:  true,
+		},
+		{
+			name:    "Retry-After - HTTP-date in the past",
+			headers: map[string]string{"Retry-After": now.Add(-5 * time.Second).Format(http.TimeFormat)},
+			want:    0,
+			wantOK:  true,
+		},
+		{
+			name:    "Retry-After - invalid",
+			headers: map[string]string{"Retry-After": "soon"},
+		},
+		{
+			name:    "RateLimit-Reset - seconds",
+			headers: map[string]string{"RateLimit-Reset": "7"},
+			want:    7 * time.Second,
+			wantOK:  true,
+		},
+		{
+			name:    "X-RateLimit-Reset - seconds",
+			headers: map[string]string{"X-RateLimit-Reset": "2"},
+			want:    2 * time.Second,
+			wantOK:  true,
+		},
+		{
+			name:    "X-RateLimit-Reset - Unix timestamp",
+			headers: map[string]string{"X-RateLimit-Reset": strconv.FormatInt(now.Add(10*time.Second).Unix(), 10)},
+			want:    10 * time.Second,
+			wantOK:  true,
+		},
+		{
+			name: "Retry-After takes precedence",
+			headers: map[string]string{
+				"Retry-After":       "1",
+				"RateLimit-Reset":   "7",
+				"X-RateLimit-Reset": "2",
+			},
+			want:   1 * time.Second,
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+
+			for k, v := range tt.headers {
+				resp.Header.Set(k, v)
+			}
+
+			got, ok := retryAfter(resp, now)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClient_Get_retryAfter(t *testing.T) {
+	tests := []struct {
+		name             string
+		clientName       string
+		retryAfter       string
+		maxWait          time.Duration
+		timeout          time.Duration
+		expectedAttempts int32
+		expectedMinWait  time.Duration
+		expectedMaxWait  time.Duration
+		wantErr          bool
+	}{
+		{
+			name:             "should wait what the server asked",
+			clientName:       "retryafterwait",
+			retryAfter:       "1",
+			maxWait:          5 * time.Second,
+			timeout:          5 * time.Second,
+			expectedAttempts: 2,
+			expectedMinWait:  1 * time.Second,
+			expectedMaxWait:  3 * time.Second,
+		},
+		{
+			name:             "should not retry if the server asks more than the max wait",
+			clientName:       "retryaftermaxwait",
+			retryAfter:       "10",
+			maxWait:          1 * time.Second,
+			timeout:          5 * time.Second,
+			expectedAttempts: 1,
+			expectedMaxWait:  1 * time.Second,
+			wantErr:          true,
+		},
+		{
+			name:             "should not retry if the wait exceeds the context deadline",
+			clientName:       "retryafterdeadline",
+			retryAfter:       "3",
+			maxWait:          5 * time.Second,
+			timeout:          1 * time.Second,
+			expectedAttempts: 1,
+			expectedMaxWait:  1 * time.Second,
+			wantErr:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+
+			// First attempt is rate limited, the second succeeds.
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) == 1 {
+					w.Header().Set("Retry-After", tt.retryAfter)
+					w.WriteHeader(http.StatusTooManyRequests)
+
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			c, err := New(tt.clientName, nil, 0, 100*time.Millisecond, 1)
+			assert.NoError(t, err)
+
+			c.RetrierMaxWait = tt.maxWait
+
+			now := time.Now()
+
+			resp, err := c.Get(ctx, server.URL)
+
+			elapsed := time.Since(now)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+
+				defer resp.Body.Close()
+			}
+
+			assert.Equal(t, tt.expectedAttempts, atomic.LoadInt32(&attempts))
+			assert.GreaterOrEqual(t, elapsed, tt.expectedMinWait)
+			assert.Less(t, elapsed, tt.expectedMaxWait)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/customerror"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// TLSConfig configures TLS, including mutual TLS (mTLS).
+type TLSConfig struct {
+	// CAFiles are PEM encoded CA bundles files, added to the system ones.
+	CAFiles []string
+
+	// CAPEM is a PEM encoded CA bundle, added to the system ones.
+	CAPEM []byte
+
+	// CertFile, and KeyFile are the PEM encoded client certificate, and key
+	// files, used for mTLS.
+	CertFile string
+	KeyFile  string
+
+	// CertPEM, and KeyPEM are the PEM encoded client certificate, and key, used
+	// for mTLS.
+	CertPEM []byte
+	KeyPEM  []byte
+
+	// MinVersion is the minimum TLS version, e.g.: `tls.VersionTLS13`. Defaults
+	// to `tls.VersionTLS12`.
+	MinVersion uint16
+
+	// ReloadInterval, if set, is how often `CertFile`, and `KeyFile` are
+	// checked for changes, reloading them without rebuilding the client.
+	//
+	// NOTE: Checks happen lazily, at TLS handshakes.
+	ReloadInterval time.Duration
+
+	// ServerName overrides the server name used to verify the server
+	// certificate, and for SNI.
+	ServerName string
+}
+
+// certReloader holds a client certificate loaded from files, reloading it
+// when the files change.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	checkedAt time.Time
+	modTime   time.Time
+}
+
+//////
+// Methods.
+//////
+
+// load loads the certificate from files.
+func (r *certReloader) load() error {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return customerror.NewFailedToError("load client certificate", customerror.WithError(err))
+	}
+
+	r.cert = &cert
+	r.modTime = modTime
+
+	return nil
+}
+
+// latestModTime returns the latest modification time of the files.
+func (r *certReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, customerror.NewFailedToError("stat client certificate", customerror.WithError(err))
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
+
+// GetClientCertificate implements `tls.Config.GetClientCertificate`. If the
+// files changed, it reloads the certificate. If reloading fails, the current
+// certificate is kept, and reloading is tried again at the next check.
+func (r *certReloader) GetClientCertificate(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.interval > 0 && time.Since(r.checkedAt) >= r.interval {
+		r.checkedAt = time.Now()
+
+		if modTime, err := r.latestModTime(); err == nil && modTime.After(r.modTime) {
+			//nolint:errcheck
+			r.load()
+		}
+	}
+
+	return r.cert, nil
+}
+
+//////
+// Factory.
+//////
+
+// NewTLSConfig creates a `tls.Config` based on `cfg`.
+func NewTLSConfig(cfg TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+
+	if cfg.MinVersion != 0 {
+		tlsConfig.MinVersion = cfg.MinVersion
+	}
+
+	//////
+	// CAs.
+	//////
+
+	if len(cfg.CAFiles) > 0 || len(cfg.CAPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		for _, file := range cfg.CAFiles {
+			b, err := os.ReadFile(file)
+			if err != nil {
+				return nil, customerror.NewFailedToError("read CA file "+file, customerror.WithError(err))
+			}
+
+			if !pool.AppendCertsFromPEM(b) {
+				return nil, customerror.NewInvalidError("CA file " + file + ", no PEM certificate found")
+			}
+		}
+
+		if len(cfg.CAPEM) > 0 && !pool.AppendCertsFromPEM(cfg.CAPEM) {
+			return nil, customerror.NewInvalidError("CA PEM, no PEM certificate found")
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	//////
+	// Client certificate.
+	//////
+
+	switch {
+	case cfg.CertFile != "" || cfg.KeyFile != "":
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return nil, customerror.NewRequiredError("both client certificate, and key files are")
+		}
+
+		reloader := &certReloader{
+			certFile:  cfg.CertFile,
+			keyFile:   cfg.KeyFile,
+			interval:  cfg.ReloadInterval,
+			checkedAt: time.Now(),
+		}
+
+		if err := reloader.load(); err != nil {
+			return nil, err
+		}
+
+		tlsConfig.GetClientCertificate = reloader.GetClientCertificate
+	case len(cfg.CertPEM) > 0 || len(cfg.KeyPEM) > 0:
+		cert, err := tls.X509KeyPair(cfg.CertPEM, cfg.KeyPEM)
+		if err != nil {
+			return nil, customerror.NewFailedToError("load client certificate", customerror.WithError(err))
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,311 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCert is a generated certificate.
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// generateTestCert generates a certificate signed by `parent`, or self-signed
+// if `parent` is nil.
+func generateTestCert(t *testing.T, commonName string, isCA bool, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+
+	parentCert, parentKey := template, key
+
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// newMTLSTestServer creates a TLS server requiring client certificates signed
+// by `ca`. It responds with the client certificate common name.
+func newMTLSTestServer(ca *testCert) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}))
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	server.StartTLS()
+
+	return server
+}
+
+// serverCAPEM returns the PEM encoded certificate of the TLS test server.
+func serverCAPEM(server *httptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+}
+
+// readBody reads, and closes the response body.
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	return string(b)
+}
+
+func TestClient_Get_TLS(t *testing.T) {
+	ca := generateTestCert(t, "ca", true, nil)
+	clientCert := generateTestCert(t, "client", false, ca)
+
+	server := newMTLSTestServer(ca)
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	assert.NoError(t, os.WriteFile(caFile, serverCAPEM(server), 0o600))
+	assert.NoError(t, os.WriteFile(certFile, clientCert.certPEM, 0o600))
+	assert.NoError(t, os.WriteFile(keyFile, clientCert.keyPEM, 0o600))
+
+	tests := []struct {
+		name       string
+		clientName string
+		tlsConfig  TLSConfig
+		wantErr    bool
+	}{
+		{
+			name:       "should work - PEM bytes",
+			clientName: "tlspem",
+			tlsConfig: TLSConfig{
+				CAPEM:   serverCAPEM(server),
+				CertPEM: clientCert.certPEM,
+				KeyPEM:  clientCert.keyPEM,
+			},
+		},
+		{
+			name:       "should work - files",
+			clientName: "tlsfiles",
+			tlsConfig: TLSConfig{
+				CAFiles:    []string{caFile},
+				CertFile:   certFile,
+				KeyFile:    keyFile,
+				MinVersion: tls.VersionTLS13,
+				ServerName: "example.com",
+			},
+		},
+		{
+			name:       "should fail - unknown CA",
+			clientName: "tlsunknownca",
+			tlsConfig: TLSConfig{
+				CertPEM: clientCert.certPEM,
+				KeyPEM:  clientCert.keyPEM,
+			},
+			wantErr: true,
+		},
+		{
+			name:       "should fail - no client certificate",
+			clientName: "tlsnoclientcert",
+			tlsConfig: TLSConfig{
+				CAPEM: serverCAPEM(server),
+			},
+			wantErr: true,
+		},
+		{
+			name:       "should fail - wrong server name",
+			clientName: "tlswrongservername",
+			tlsConfig: TLSConfig{
+				CAPEM:      serverCAPEM(server),
+				CertPEM:    clientCert.certPEM,
+				KeyPEM:     clientCert.keyPEM,
+				ServerName: "wrong.com",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			c, err := Initialize(
+				WithClientName(tt.clientName),
+				WithClientRetryPolicy(NeverRetryPolicy),
+				WithClientTLSConfig(tt.tlsConfig),
+			)
+			assert.NoError(t, err)
+
+			resp, err := c.Get(ctx, server.URL)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "client", readBody(t, resp))
+		})
+	}
+}
+
+func TestClient_Get_TLS_reload(t *testing.T) {
+	ca := generateTestCert(t, "ca", true, nil)
+
+	server := newMTLSTestServer(ca)
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	// writeClientCert writes a new client certificate, bumping the files
+	// modification time.
+	writeClientCert := func(commonName string, modTime time.Time) {
+		clientCert := generateTestCert(t, commonName, false, ca)
+
+		assert.NoError(t, os.WriteFile(certFile, clientCert.certPEM, 0o600))
+		assert.NoError(t, os.WriteFile(keyFile, clientCert.keyPEM, 0o600))
+		assert.NoError(t, os.Chtimes(certFile, modTime, modTime))
+		assert.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+	}
+
+	writeClientCert("client1", time.Now())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Initialize(
+		WithClientName("tlsreload"),
+		WithClientRetryPolicy(NeverRetryPolicy),
+		// Forces a TLS handshake per request.
+		WithClientTransportConfig(TransportConfig{DisableKeepAlives: true}),
+		WithClientTLSConfig(TLSConfig{
+			CAPEM:          serverCAPEM(server),
+			CertFile:       certFile,
+			KeyFile:        keyFile,
+			ReloadInterval: time.Millisecond,
+		}),
+	)
+	assert.NoError(t, err)
+
+	resp, err := c.Get(ctx, server.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, "client1", readBody(t, resp))
+
+	writeClientCert("client2", time.Now().Add(time.Minute))
+
+	time.Sleep(10 * time.Millisecond)
+
+	resp, err = c.Get(ctx, server.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, "client2", readBody(t, resp))
+}
+
+func TestInitialize_invalidTLSConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		clientName string
+		opts       []ClientFunc
+	}{
+		{
+			name:       "missing key file",
+			clientName: "tlsmissingkey",
+			opts: []ClientFunc{
+				WithClientTLSConfig(TLSConfig{CertFile: "cert.pem"}),
+			},
+		},
+		{
+			name:       "invalid CA PEM",
+			clientName: "tlsinvalidca",
+			opts: []ClientFunc{
+				WithClientTLSConfig(TLSConfig{CAPEM: []byte("invalid")}),
+			},
+		},
+		{
+			name:       "non *http.Transport transport",
+			clientName: "tlsinvalidtransport",
+			opts: []ClientFunc{
+				WithClientTransport(roundTripperFunc(http.DefaultTransport.RoundTrip)),
+				WithClientTLSConfig(TLSConfig{}),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Initialize(append([]ClientFunc{WithClientName(tt.clientName)}, tt.opts...)...)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// roundTripperFunc allows to use a function as a `http.RoundTripper`.
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip implements the http.RoundTripper interface.
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,156 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// TracerName is the instrumentation name of the client's tracer.
+const TracerName = "github.com/thalesfsp/httpclient"
+
+// endSpanFunc ends a span, recording the outcome.
+type endSpanFunc func(resp *http.Response, err error)
+
+//////
+// Methods.
+//////
+
+// startSpan starts the span of a request, returning the context carrying it,
+// and the function ending it. No-op if tracing isn't enabled, see
+// `WithClientTracerProvider`.
+//
+// NOTE: It's an internal span, only attempts are client ones, otherwise trace
+// backends would count each request twice.
+func (c *Client) startSpan(ctx context.Context, req *http.Request) (context.Context, endSpanFunc) {
+	if c.tracer == nil {
+		return ctx, func(*http.Response, error) {}
+	}
+
+	ctx, span := c.tracer.Start(
+		ctx,
+		req.Method,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(c.spanAttributes(req)...),
+	)
+
+	return ctx, c.endSpan(span, req.URL.String())
+}
+
+// startAttemptSpan starts the span of an attempt, child of the request span in
+// the `req` context, injecting the trace context into the `req` headers. No-op
+// if tracing isn't enabled.
+func (c *Client) startAttemptSpan(req *http.Request, attempt int) (*http.Request, endSpanFunc) {
+	if c.tracer == nil {
+		return req, func(*http.Response, error) {}
+	}
+
+	attributes := c.spanAttributes(req)
+
+	if attempt > 1 {
+		attributes = append(attributes, semconv.HTTPResendCount(attempt-1))
+	}
+
+	ctx, span := c.tracer.Start(
+		req.Context(),
+		req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...),
+	)
+
+	req = req.WithContext(ctx)
+
+	c.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	return req, c.endSpan(span, req.URL.String())
+}
+
+// spanAttributes returns the semantic-convention attributes of `req`.
+//
+// NOTE: The URL is redacted, see `Redactor`.
+func (c *Client) spanAttributes(req *http.Request) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLFull(c.Redactor.URL(req.URL.String())),
+		semconv.ServerAddress(req.URL.Hostname()),
+	}
+
+	if port := urlPort(req); port > 0 {
+		attributes = append(attributes, semconv.ServerPort(port))
+	}
+
+	return attributes
+}
+
+// endSpan returns the function ending `span`. Status codes >= 400, and errors
+// mark the span as failed.
+func (c *Client) endSpan(span trace.Span, rawURL string) endSpanFunc {
+	return func(resp *http.Response, err error) {
+		defer span.End()
+
+		statusCode := statusCodeOf(resp, err)
+
+		if statusCode > 0 {
+			span.SetAttributes(semconv.HTTPResponseStatusCode(statusCode))
+		}
+
+		if err != nil {
+			msg := c.Redactor.Message(err.Error(), rawURL)
+
+			span.RecordError(errors.New(msg))
+			span.SetStatus(codes.Error, msg)
+
+			return
+		}
+
+		if statusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, http.StatusText(statusCode))
+		}
+	}
+}
+
+//////
+// Helpers.
+//////
+
+// statusCodeOf returns the status code of `resp`, or of `err` if it's an
+// `HTTPError`. Zero if none.
+func statusCodeOf(resp *http.Response, err error) int {
+	if resp != nil {
+		return resp.StatusCode
+	}
+
+	var httpErr *HTTPError
+
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode
+	}
+
+	return 0
+}
+
+// urlPort returns the port of the `req` URL, defaulting to the scheme's one.
+func urlPort(req *http.Request) int {
+	if port, err := strconv.Atoi(req.URL.Port()); err == nil {
+		return port
+	}
+
+	port, err := net.LookupPort("tcp", req.URL.Scheme)
+	if err != nil {
+		return 0
+	}
+
+	return port
+}
@@ -0,0 +1,148 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestClient_Get_tracing(t *testing.T) {
+	var (
+		hits         int32
+		mu           sync.Mutex
+		traceparents []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		mu.Unlock()
+
+		// Fails the first attempt.
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	c, err := Initialize(
+		WithClientName("tracing"),
+		WithClientRetrier(100*time.Millisecond, 1),
+		WithClientTracerProvider(tracerProvider),
+	)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// A parent span, e.g.: the caller's server one.
+	ctx, parent := tracerProvider.Tracer("test").Start(ctx, "parent")
+
+	resp, err := c.Get(ctx, server.URL+"?token=secret")
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	parent.End()
+
+	// 2 attempts, the request, and the parent.
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 4)
+
+	firstAttempt, secondAttempt, request := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, parent.SpanContext().TraceID(), request.SpanContext.TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), request.Parent.SpanID())
+	assert.Equal(t, trace.SpanKindInternal, request.SpanKind)
+	assert.Equal(t, http.MethodGet, request.Name)
+	assert.Equal(t, codes.Unset, request.Status.Code)
+
+	for _, attempt := range []tracetest.SpanStub{firstAttempt, secondAttempt} {
+		assert.Equal(t, request.SpanContext.SpanID(), attempt.Parent.SpanID())
+		assert.Equal(t, trace.SpanKindClient, attempt.SpanKind)
+	}
+
+	assert.Equal(t, codes.Error, firstAttempt.Status.Code)
+	assert.Contains(t, firstAttempt.Attributes, semconv.HTTPResponseStatusCode(http.StatusInternalServerError))
+	assert.Contains(t, secondAttempt.Attributes, semconv.HTTPResponseStatusCode(http.StatusOK))
+	assert.Contains(t, secondAttempt.Attributes, semconv.HTTPResendCount(1))
+	assert.Contains(t, request.Attributes, semconv.HTTPRequestMethodKey.String(http.MethodGet))
+	assert.Contains(t, request.Attributes, semconv.ServerAddress("127.0.0.1"))
+	assert.Contains(t, request.Attributes, semconv.URLFull(server.URL+"?token=[REDACTED]"))
+
+	// Each attempt propagates its own span.
+	assert.Equal(t, []string{
+		"00-" + firstAttempt.SpanContext.TraceID().String() + "-" + firstAttempt.SpanContext.SpanID().String() + "-01",
+		"00-" + secondAttempt.SpanContext.TraceID().String() + "-" + secondAttempt.SpanContext.SpanID().String() + "-01",
+	}, traceparents)
+}
+
+func TestClient_Get_tracingFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+
+	c, err := Initialize(
+		WithClientName("tracingfailure"),
+		WithClientTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))),
+	)
+	assert.NoError(t, err)
+
+	//nolint:bodyclose
+	_, err = c.Get(context.Background(), server.URL)
+	assert.True(t, IsNotFound(err))
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+
+	for _, span := range spans {
+		assert.Equal(t, codes.Error, span.Status.Code)
+		assert.Contains(t, span.Attributes, semconv.HTTPResponseStatusCode(http.StatusNotFound))
+	}
+
+	// The request span records the error.
+	assert.Len(t, spans[1].Events, 1)
+	assert.Contains(t, spans[1].Events[0].Attributes, attribute.String("exception.type", "*errors.errorString"))
+}
+
+func TestClient_Get_tracingDisabled(t *testing.T) {
+	var traceparent string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := Initialize(WithClientName("tracingdisabled"))
+	assert.NoError(t, err)
+
+	resp, err := c.Get(context.Background(), server.URL)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Empty(t, traceparent)
+}
@@ -0,0 +1,151 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/thalesfsp/customerror"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// TransportConfig tunes the underlying transport, and its connection pool.
+// Zero values fall back to the `http.DefaultTransport` ones.
+type TransportConfig struct {
+	// DialTimeout is the max amount of time a dial waits for a connect.
+	DialTimeout time.Duration
+
+	// DisableHTTP2 prevents upgrading to HTTP/2.
+	DisableHTTP2 bool
+
+	// DisableKeepAlives disables connection reuse.
+	DisableKeepAlives bool
+
+	// IdleConnTimeout is the max amount of time an idle connection remains in
+	// the pool.
+	IdleConnTimeout time.Duration
+
+	// KeepAlive is the interval between keep-alive probes.
+	KeepAlive time.Duration
+
+	// MaxConnsPerHost limits the total number of connections per host. Zero
+	// means no limit.
+	MaxConnsPerHost int
+
+	// MaxIdleConns limits the number of idle connections across all hosts.
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost limits the number of idle connections per host.
+	MaxIdleConnsPerHost int
+
+	// ResponseHeaderTimeout is the max amount of time to wait for the response
+	// headers, after writing the request.
+	ResponseHeaderTimeout time.Duration
+
+	// TLSHandshakeTimeout is the max amount of time waiting for a TLS handshake.
+	TLSHandshakeTimeout time.Duration
+}
+
+//////
+// Factory.
+//////
+
+// NewTransport creates a transport, based on `http.DefaultTransport`, tuned by
+// `cfg`.
+func NewTransport(cfg TransportConfig) *http.Transport {
+	//nolint:forcetypeassert
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.DialTimeout > 0 || cfg.KeepAlive > 0 {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}
+
+		if cfg.DialTimeout > 0 {
+			dialer.Timeout = cfg.DialTimeout
+		}
+
+		if cfg.KeepAlive > 0 {
+			dialer.KeepAlive = cfg.KeepAlive
+		}
+
+		transport.DialContext = dialer.DialContext
+	}
+
+	if cfg.DisableHTTP2 {
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	transport.DisableKeepAlives = cfg.DisableKeepAlives
+
+	if cfg.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+
+	if cfg.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = cfg.MaxConnsPerHost
+	}
+
+	if cfg.MaxIdleConns > 0 {
+		transport.MaxIdleConns = cfg.MaxIdleConns
+	}
+
+	if cfg.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+
+	if cfg.ResponseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = cfg.ResponseHeaderTimeout
+	}
+
+	if cfg.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = cfg.TLSHandshakeTimeout
+	}
+
+	return transport
+}
+
+// newClientTransport resolves the transport of the underlying HTTP client. A
+// provided transport takes precedence over the transport config. TLS config,
+// if any, is applied to a copy of it.
+func newClientTransport(o *ClientOptions) (http.RoundTripper, error) {
+	if o.TLSConfig == nil {
+		if o.Transport == nil && o.TransportConfig != nil {
+			return NewTransport(*o.TransportConfig), nil
+		}
+
+		return o.Transport, nil
+	}
+
+	tlsConfig, err := NewTLSConfig(*o.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var transport *http.Transport
+
+	switch t := o.Transport.(type) {
+	case nil:
+		cfg := TransportConfig{}
+
+		if o.TransportConfig != nil {
+			cfg = *o.TransportConfig
+		}
+
+		transport = NewTransport(cfg)
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, customerror.NewInvalidError("transport, TLS config requires a *http.Transport")
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
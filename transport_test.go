@@ -0,0 +1,86 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTransport(t *testing.T) {
+	transport := NewTransport(TransportConfig{
+		DisableHTTP2:          true,
+		IdleConnTimeout:       10 * time.Second,
+		MaxConnsPerHost:       20,
+		MaxIdleConns:          50,
+		MaxIdleConnsPerHost:   25,
+		ResponseHeaderTimeout: 5 * time.Second,
+		TLSHandshakeTimeout:   3 * time.Second,
+	})
+
+	assert.False(t, transport.ForceAttemptHTTP2)
+	assert.NotNil(t, transport.TLSNextProto)
+	assert.Empty(t, transport.TLSNextProto)
+	assert.Equal(t, 10*time.Second, transport.IdleConnTimeout)
+	assert.Equal(t, 20, transport.MaxConnsPerHost)
+	assert.Equal(t, 50, transport.MaxIdleConns)
+	assert.Equal(t, 25, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 5*time.Second, transport.ResponseHeaderTimeout)
+	assert.Equal(t, 3*time.Second, transport.TLSHandshakeTimeout)
+
+	// Zero values fall back to the default transport ones.
+	//nolint:forcetypeassert
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+
+	transport = NewTransport(TransportConfig{})
+
+	assert.True(t, transport.ForceAttemptHTTP2)
+	assert.Equal(t, defaultTransport.IdleConnTimeout, transport.IdleConnTimeout)
+	assert.Equal(t, defaultTransport.MaxIdleConns, transport.MaxIdleConns)
+	assert.Equal(t, defaultTransport.TLSHandshakeTimeout, transport.TLSHandshakeTimeout)
+}
+
+func TestClient_Get_keepAlive(t *testing.T) {
+	var connections int32
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+
+	server.Start()
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Initialize(
+		WithClientName("keepalive"),
+		WithClientTransportConfig(TransportConfig{MaxIdleConnsPerHost: 10}),
+	)
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		resp, err := c.Get(ctx, server.URL)
+		assert.NoError(t, err)
+
+		_, err = io.Copy(io.Discard, resp.Body)
+		assert.NoError(t, err)
+
+		assert.NoError(t, resp.Body.Close())
+	}
+
+	// Connection is reused.
+	assert.Equal(t, int32(1), atomic.LoadInt32(&connections))
+}
@@ -0,0 +1,78 @@
+package httpclient
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/thalesfsp/customerror"
+)
+
+//////
+// Vars, consts, and types.
+//////
+
+// pathParamRegex matches path param placeholders, e.g.: `{id}`.
+var pathParamRegex = regexp.MustCompile(`\{[^{}/]+\}`)
+
+//////
+// Methods.
+//////
+
+// resolveURL fills path params placeholders (e.g.: `{id}`) in `rawURL`, and, if
+// it's relative, resolves it against the client's base URL.
+//
+// NOTE: Relative paths are appended to the base URL path, e.g.:
+// `http://host/api/v1` + `users/{id}` -> `http://host/api/v1/users/1`.
+func (c *Client) resolveURL(rawURL string, pathParams map[string]string) (string, error) {
+	for k, v := range pathParams {
+		rawURL = strings.ReplaceAll(rawURL, "{"+k+"}", url.PathEscape(v))
+	}
+
+	// Only the path is checked, query params are left as they are. Without
+	// path params, nor base URL, braces are kept as they are, as they used to.
+	if len(pathParams) > 0 || c.BaseURL != "" {
+		path, _, _ := strings.Cut(rawURL, "?")
+
+		if missing := pathParamRegex.FindString(path); missing != "" {
+			return "", customerror.NewMissingError("path param " + missing)
+		}
+	}
+
+	if c.BaseURL == "" {
+		return rawURL, nil
+	}
+
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", customerror.NewInvalidError("url", customerror.WithError(err))
+	}
+
+	if parsedURL.IsAbs() {
+		return rawURL, nil
+	}
+
+	if rawURL == "" {
+		return c.BaseURL, nil
+	}
+
+	if strings.HasPrefix(rawURL, "?") {
+		return c.BaseURL + rawURL, nil
+	}
+
+	return strings.TrimSuffix(c.BaseURL, "/") + "/" + strings.TrimPrefix(rawURL, "/"), nil
+}
+
+// routeOf returns the route of `rawURL`: the URL template, before path params
+// are filled, if any is, see `WithPathParam`. Otherwise, it's empty, as raw
+// paths, e.g.: `/users/1`, would make metrics, and rate limit buckets grow
+// without bound.
+func routeOf(rawURL string, pathParams map[string]string) string {
+	for k := range pathParams {
+		if strings.Contains(rawURL, "{"+k+"}") {
+			return rawURL
+		}
+	}
+
+	return ""
+}
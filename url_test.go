@@ -0,0 +1,138 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_resolveURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		baseURL    string
+		rawURL     string
+		pathParams map[string]string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:   "no base URL",
+			rawURL: "http://localhost/users",
+			want:   "http://localhost/users",
+		},
+		{
+			name:    "absolute URL ignores base URL",
+			baseURL: "http://localhost/api",
+			rawURL:  "http://example.com/users",
+			want:    "http://example.com/users",
+		},
+		{
+			name:    "relative path",
+			baseURL: "http://localhost/api/v1",
+			rawURL:  "users",
+			want:    "http://localhost/api/v1/users",
+		},
+		{
+			name:    "relative path - leading, and trailing slashes",
+			baseURL: "http://localhost/api/v1/",
+			rawURL:  "/users",
+			want:    "http://localhost/api/v1/users",
+		},
+		{
+			name:    "relative path - query params",
+			baseURL: "http://localhost/api",
+			rawURL:  "users?name={name}",
+			want:    "http://localhost/api/users?name={name}",
+		},
+		{
+			name:    "empty path",
+			baseURL: "http://localhost/api",
+			rawURL:  "",
+			want:    "http://localhost/api",
+		},
+		{
+			name:       "path params - escaped",
+			baseURL:    "http://localhost/api",
+			rawURL:     "users/{id}/files/{file}",
+			pathParams: map[string]string{"id": "1", "file": "a b/c"},
+			want:       "http://localhost/api/users/1/files/a%20b%2Fc",
+		},
+		{
+			name:       "path params - no base URL",
+			rawURL:     "http://localhost/users/{id}",
+			pathParams: map[string]string{"id": "1"},
+			want:       "http://localhost/users/1",
+		},
+		{
+			name:   "no path params, nor base URL - literal braces",
+			rawURL: "http://localhost/search/{literal}",
+			want:   "http://localhost/search/{literal}",
+		},
+		{
+			name:       "path params - missing, no base URL",
+			rawURL:     "http://localhost/users/{id}/files/{file}",
+			pathParams: map[string]string{"id": "1"},
+			wantErr:    true,
+		},
+		{
+			name:    "path params - missing",
+			baseURL: "http://localhost/api",
+			rawURL:  "users/{id}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{BaseURL: tt.baseURL}
+
+			got, err := c.resolveURL(tt.rawURL, tt.pathParams)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClient_Get_baseURL(t *testing.T) {
+	var path string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Initialize(
+		WithClientName("baseurl"),
+		WithClientBaseURL(server.URL+"/api/v1"),
+	)
+	assert.NoError(t, err)
+
+	resp, err := c.Get(ctx, "/users/{id}", WithPathParam("id", "john doe"))
+	assert.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, "/api/v1/users/john%20doe", path)
+}
+
+func TestInitialize_invalidBaseURL(t *testing.T) {
+	_, err := Initialize(
+		WithClientName("invalidbaseurl"),
+		WithClientBaseURL("localhost"),
+	)
+	assert.Error(t, err)
+}